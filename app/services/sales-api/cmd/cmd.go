@@ -6,10 +6,21 @@ import (
 	"expvar"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"runtime"
 	"sales-api/app/services/sales-api/handlers"
+	"sales-api/business/core/decision"
+	"sales-api/business/core/decision/stores/decisiondb"
+	"sales-api/business/core/department"
+	"sales-api/business/core/department/stores/departmentdb"
+	"sales-api/business/core/role"
+	"sales-api/business/core/role/stores/roledb"
+	"sales-api/business/core/session"
+	"sales-api/business/core/session/stores/sessiondb"
+	"sales-api/business/core/user"
+	"sales-api/business/core/user/stores/userdb"
 	"sales-api/business/data/dbsql/pgx"
 	v1 "sales-api/business/web/v1"
 	"sales-api/business/web/v1/auth"
@@ -17,6 +28,7 @@ import (
 	"sales-api/foundation/keystore"
 	"sales-api/foundation/logger"
 	"sales-api/foundation/web"
+	"slices"
 	"syscall"
 	"time"
 
@@ -60,20 +72,34 @@ func run(ctx context.Context, log *logger.Logger, build string) error {
 			ShutdownTimeout time.Duration `conf:"default:20s"`
 			APIHost         string        `conf:"default:0.0.0.0:3000"`
 			DebugHost       string        `conf:"default:0.0.0.0:4000"`
+			PublicURL       string        `conf:"default:http://localhost:3000,help:address clients reach the API at"`
 		}
 		Auth struct {
-			KeysFolder string `conf:"default:zarf/keys/"`
-			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
-			Issuer     string `conf:"default:service project"`
+			KeysFolder      string        `conf:"default:zarf/keys/"`
+			ActiveKID       string        `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
+			RetiredKIDs     []string      `conf:"help:keys kept in the folder that no longer verify tokens"`
+			KeysRescan      time.Duration `conf:"default:1m,help:how often the keys folder is scanned for changes, 0 disables it"`
+			KeysGrace       time.Duration `conf:"default:1h,help:how long a removed key keeps verifying tokens"`
+			Issuer          string        `conf:"default:service project"`
+			AcceptedIssuers []string      `conf:"help:other issuers whose tokens are accepted"`
+			Audience        string        `conf:"default:sales-api"`
+			Leeway          time.Duration `conf:"default:30s,help:clock skew allowed when checking token times"`
+			PolicyPath      string        `conf:"help:opa bundle directory or tarball, the embedded policies are used when empty"`
+			PolicyReload    time.Duration `conf:"default:1m,help:how often the policy bundle is loaded again, 0 disables it"`
+			DecisionSinks   []string      `conf:"default:log;db,help:where authorization decisions are recorded: log and/or db, db inserts on every authorized request"`
+			DecisionKeep    time.Duration `conf:"default:720h,help:how long decisions recorded in the db are kept, 0 keeps them forever"`
+			DecisionPurge   time.Duration `conf:"default:1h,help:how often expired sessions and decisions past DecisionKeep are removed, 0 disables it"`
 		}
 		DB struct {
-			User         string `conf:"default:postgres"`
-			Password     string `conf:"default:postgres,mask"`
-			Host         string `conf:"default:database-service.sales-system.svc.cluster.local"`
-			Name         string `conf:"default:postgres"`
-			MaxIdleConns int    `conf:"default:2"`
-			MaxOpenConns int    `conf:"default:0"`
-			DisableTLS   bool   `conf:"default:true"`
+			User                string `conf:"default:sales_app"`
+			Password            string `conf:"default:postgres,mask"`
+			MaintenanceUser     string `conf:"default:sales_maintenance,help:role for work that crosses tenants, it bypasses row level security"`
+			MaintenancePassword string `conf:"default:postgres,mask"`
+			Host                string `conf:"default:database-service.sales-system.svc.cluster.local"`
+			Name                string `conf:"default:postgres"`
+			MaxIdleConns        int    `conf:"default:2"`
+			MaxOpenConns        int    `conf:"default:0"`
+			DisableTLS          bool   `conf:"default:true"`
 		}
 		Tempo struct {
 			ReporterURI string  `conf:"default:tempo.sales-system.svc.cluster.local:4317"`
@@ -125,7 +151,22 @@ func run(ctx context.Context, log *logger.Logger, build string) error {
 		return fmt.Errorf("connecting to db: %w", err)
 	}
 
-	log.Info(ctx, "startup", "status", "initializing database support", "host", cfg.DB.Host)
+	// Requests run as a role the row level security policies apply to. The
+	// few paths that can't know the tenant up front use their own pool.
+	maintDB, err := pgx.Open(pgx.Config{
+		User:         cfg.DB.MaintenanceUser,
+		Password:     cfg.DB.MaintenancePassword,
+		Host:         cfg.DB.Host,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+	})
+	if err != nil {
+		return fmt.Errorf("connecting to maintenance db: %w", err)
+	}
+
+	log.Info(ctx, "startup", "status", "initializing database support", "host", cfg.DB.Host, "user", cfg.DB.User, "maintenance", cfg.DB.MaintenanceUser)
 
 	// -------------------------------------------------------------------------
 	// Initialize authentication support
@@ -138,10 +179,59 @@ func run(ctx context.Context, log *logger.Logger, build string) error {
 		return fmt.Errorf("reading keys: %w", err)
 	}
 
+	// During a rotation the old key stays in the folder so tokens it signed
+	// keep working. Once they have expired the key is retired.
+	for _, kid := range cfg.Auth.RetiredKIDs {
+		if err := ks.Retire(kid); err != nil {
+			return fmt.Errorf("retiring key[%s]: %w", kid, err)
+		}
+	}
+
+	log.Info(ctx, "startup", "status", "keys loaded", "active", ks.ActiveKIDs(), "retired", ks.RetiredKIDs(), "signing", cfg.Auth.ActiveKID)
+
+	// The caches are shared by every core of the service, so a change made
+	// through the handlers is seen when authenticating.
+	usrCache := user.NewEnabledCache()
+	rolCache := role.NewCache(roledb.NewRepository(log, maintDB))
+
+	usrCore := user.NewCore(log, usrCache, userdb.NewRepository(log, db))
+	depCore := department.NewCore(log, usrCore, departmentdb.NewRepository(log, db))
+	rolCore := role.NewCore(log, rolCache, roledb.NewRepository(log, db))
+	sesCore := session.NewCore(log, sessiondb.NewRepository(log, maintDB))
+	decCore := decision.NewCore(log, decisiondb.NewRepository(log, db))
+
+	// Load the roles up front so tokens carrying custom roles can be parsed
+	// before anything is authorized.
+	if err := rolCore.Load(ctx); err != nil {
+		return fmt.Errorf("loading roles: %w", err)
+	}
+
+	var sinks []auth.DecisionSink
+	for _, sink := range cfg.Auth.DecisionSinks {
+		switch sink {
+		case "log":
+			sinks = append(sinks, auth.NewLogSink(log))
+		case "db":
+			sinks = append(sinks, decCore)
+		default:
+			return fmt.Errorf("unknown decision sink %q", sink)
+		}
+	}
+
 	authCfg := auth.Config{
-		Log:       log,
-		KeyLookup: ks,
-		Issuer:    cfg.Auth.Issuer,
+		Log:             log,
+		KeyLookup:       ks,
+		ActiveKID:       cfg.Auth.ActiveKID,
+		Issuer:          cfg.Auth.Issuer,
+		AcceptedIssuers: cfg.Auth.AcceptedIssuers,
+		Audience:        cfg.Auth.Audience,
+		Leeway:          cfg.Auth.Leeway,
+		PolicyPath:      cfg.Auth.PolicyPath,
+		ReportingChain:  depCore,
+		Permissions:     rolCore,
+		Users:           usrCore,
+		Revocations:     sesCore,
+		Decisions:       sinks,
 	}
 
 	auth, err := auth.New(authCfg)
@@ -149,6 +239,113 @@ func run(ctx context.Context, log *logger.Logger, build string) error {
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	log.Info(ctx, "startup", "status", "policies loaded", "path", cfg.Auth.PolicyPath, "version", auth.PolicyVersion())
+
+	// -------------------------------------------------------------------------
+	// Start Key Rotation Support
+
+	// Keys are rotated by adding and removing PEM files in the keys folder,
+	// the folder is scanned again so that doesn't need a restart.
+	reloadDone := make(chan struct{})
+	defer close(reloadDone)
+
+	if cfg.Auth.KeysRescan > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Auth.KeysRescan)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					kids, err := ks.Rescan(cfg.Auth.KeysGrace)
+					if err != nil {
+						log.Error(ctx, "keys", "status", "rescan failed", "folder", cfg.Auth.KeysFolder, "msg", err)
+						continue
+					}
+					if len(kids) == 0 {
+						continue
+					}
+
+					auth.InvalidateKeys(kids...)
+					log.Info(ctx, "keys", "status", "keys changed", "kids", kids, "active", ks.ActiveKIDs(), "retired", ks.RetiredKIDs())
+
+					if _, err := ks.PrivateKey(cfg.Auth.ActiveKID); err != nil {
+						log.Error(ctx, "keys", "status", "signing key unavailable", "kid", cfg.Auth.ActiveKID, "msg", err)
+					}
+
+				case <-reloadDone:
+					return
+				}
+			}
+		}()
+	}
+
+	// -------------------------------------------------------------------------
+	// Start Policy Reload Support
+
+	// The policy bundle is loaded again so policies and data documents can
+	// be changed without a restart. A bundle that fails to load leaves the
+	// policies in use alone.
+	if cfg.Auth.PolicyPath != "" && cfg.Auth.PolicyReload > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Auth.PolicyReload)
+			defer ticker.Stop()
+
+			version := auth.PolicyVersion()
+			for {
+				select {
+				case <-ticker.C:
+					v, err := auth.ReloadPolicies(ctx)
+					if err != nil {
+						log.Error(ctx, "policies", "status", "reload failed", "path", cfg.Auth.PolicyPath, "version", v, "msg", err)
+						continue
+					}
+					if v != version {
+						log.Info(ctx, "policies", "status", "policies changed", "from", version, "to", v)
+						version = v
+					}
+
+				case <-reloadDone:
+					return
+				}
+			}
+		}()
+	}
+
+	// -------------------------------------------------------------------------
+	// Start Retention Support
+
+	// Expired sessions are removed, and so are decisions recorded in the
+	// database once they are older than DecisionKeep. That spans every
+	// tenant, so it runs as the maintenance role.
+	if cfg.Auth.DecisionPurge > 0 {
+		purgeDecisions := cfg.Auth.DecisionKeep > 0 && slices.Contains(cfg.Auth.DecisionSinks, "db")
+		decPurge := decision.NewCore(log, decisiondb.NewRepository(log, maintDB))
+
+		go func() {
+			ticker := time.NewTicker(cfg.Auth.DecisionPurge)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					if err := sesCore.DeleteExpired(ctx); err != nil {
+						log.Error(ctx, "sessions", "status", "purge failed", "msg", err)
+					}
+
+					if purgeDecisions {
+						if err := decPurge.DeleteBefore(ctx, time.Now().Add(-cfg.Auth.DecisionKeep)); err != nil {
+							log.Error(ctx, "decisions", "status", "purge failed", "msg", err)
+						}
+					}
+
+				case <-reloadDone:
+					return
+				}
+			}
+		}()
+	}
+
 	// -------------------------------------------------------------------------
 	// Start Debug Service
 
@@ -164,14 +361,27 @@ func run(ctx context.Context, log *logger.Logger, build string) error {
 	// Start API Service
 
 	log.Info(ctx, "startup", "status", "initializing V1 API support")
+
+	// Addresses handed out to clients, like calendar feeds, are built from
+	// the public address rather than whatever host a request came in on.
+	publicURL, err := url.Parse(cfg.Web.PublicURL)
+	if err != nil || publicURL.Scheme == "" || publicURL.Host == "" {
+		return fmt.Errorf("parsing public url[%s]: must be an absolute url", cfg.Web.PublicURL)
+	}
+
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 	apiCfg := v1.APIMuxConfig{
-		Build:    build,
-		Shutdown: shutdown,
-		Log:      log,
-		Auth:     auth,
-		DB:       db,
+		Build:         build,
+		Shutdown:      shutdown,
+		Log:           log,
+		Auth:          auth,
+		Keys:          ks,
+		DB:            db,
+		MaintenanceDB: maintDB,
+		UserCache:     usrCache,
+		RoleCache:     rolCache,
+		PublicURL:     publicURL,
 	}
 
 	handler := v1.APIMux(apiCfg, handlers.Routes())
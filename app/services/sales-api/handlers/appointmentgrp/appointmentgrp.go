@@ -0,0 +1,279 @@
+package appointmentgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sales-api/business/core/appointment"
+	"sales-api/business/data/actor"
+	"sales-api/business/data/order"
+	"sales-api/business/data/page"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/response"
+	"sales-api/foundation/validate"
+	"sales-api/foundation/web"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for handling appointment group errors.
+var (
+	ErrInvalidID = errors.New("ID is not in its proper form")
+)
+
+// The calendar feed holds appointments that ended within feedHistory, up to
+// feedLimit of them.
+const (
+	feedHistory = 90 * 24 * time.Hour
+	feedLimit   = 1000
+)
+
+// Handlers manages the set of appointment endpoints. A calendar feed is
+// found by its token before the tenant is known, feed runs where row level
+// security doesn't apply.
+type Handlers struct {
+	appointment *appointment.Core
+	feed        *appointment.Core
+	publicURL   *url.URL
+}
+
+// New constructs a handlers for route access. Feed addresses are built from
+// publicURL, the address clients reach the API at.
+func New(appointment *appointment.Core, feed *appointment.Core, publicURL *url.URL) *Handlers {
+	return &Handlers{
+		appointment: appointment,
+		feed:        feed,
+		publicURL:   publicURL,
+	}
+}
+
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		appointment, err := h.appointment.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+		h = &Handlers{
+			appointment: appointment,
+			feed:        h.feed,
+			publicURL:   h.publicURL,
+		}
+		return h, nil
+	}
+	return h, nil
+}
+
+// Create adds a new appointment to the user's calendar.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppNewAppointment
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	na, err := toCoreNewAppointment(auth.GetUserID(ctx), app)
+	if err != nil {
+		return err
+	}
+
+	appt, err := h.appointment.Create(ctx, na)
+	if err != nil {
+		if rerr := toResponseError(err); rerr != nil {
+			return rerr
+		}
+		return fmt.Errorf("create: appt[%+v]: %w", na, err)
+	}
+
+	return web.Respond(ctx, w, appointmentResponse(appt), http.StatusCreated)
+}
+
+// QueryByID returns an appointment by its ID.
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	appt, err := h.queryAppointment(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, appointmentResponse(appt), http.StatusOK)
+}
+
+// UpdateByID updates an appointment by its ID.
+func (h *Handlers) UpdateByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppUpdateAppointment
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	appt, err := h.queryAppointment(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	ua, err := toCoreUpdateAppointment(app)
+	if err != nil {
+		return err
+	}
+
+	appt, err = h.appointment.Update(ctx, appt, ua)
+	if err != nil {
+		if rerr := toResponseError(err); rerr != nil {
+			return rerr
+		}
+		return fmt.Errorf("update: appointmentID[%s] ua[%+v]: %w", appt.ID, ua, err)
+	}
+
+	return web.Respond(ctx, w, appointmentResponse(appt), http.StatusOK)
+}
+
+// DeleteByID removes an appointment by its ID.
+func (h *Handlers) DeleteByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	appt, err := h.queryAppointment(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.appointment.Delete(ctx, appt.ID); err != nil {
+		return fmt.Errorf("delete: appointmentID[%s]: %w", appt.ID, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Query returns a list of the user's appointments with paging.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := page.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+	filter.WithOwnerID(auth.GetUserID(ctx))
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	appts, err := h.appointment.Query(ctx, filter, orderBy, page.Page, page.PageSize)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	total, err := h.appointment.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewPageDocument(toAppAppointments(appts), total, page.Page, page.PageSize), http.StatusOK)
+}
+
+// RotateFeed issues a new calendar feed address for the user. Any address
+// handed out before stops working.
+func (h *Handlers) RotateFeed(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	token, err := h.appointment.RotateFeedToken(ctx, auth.GetUserID(ctx))
+	if err != nil {
+		if errors.Is(err, appointment.ErrOwnerNotFound) {
+			return response.NewError(appointment.ErrOwnerNotFound, http.StatusNotFound)
+		}
+		return fmt.Errorf("rotatefeedtoken: %w", err)
+	}
+
+	u := h.publicURL.JoinPath("v1/appointments/feed", token+".ics")
+
+	return web.Respond(ctx, w, response.NewSuccess(AppFeed{URL: u.String()}), http.StatusCreated)
+}
+
+// Feed returns the user's appointments as an iCalendar document. Calendar
+// apps can't send an authorization header, so the secret token in the
+// address authenticates the request.
+func (h *Handlers) Feed(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	feed, err := h.feed.QueryFeedByToken(ctx, web.Param(r, "token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrFeedNotFound), errors.Is(err, appointment.ErrFeedTokenMalformed):
+			return response.NewError(appointment.ErrFeedNotFound, http.StatusNotFound)
+		default:
+			return fmt.Errorf("queryfeedbytoken: %w", err)
+		}
+	}
+
+	// There are no claims to take the tenant from, the feed carries it. The
+	// feed's owner is who reads their appointments.
+	ctx = tenant.Set(ctx, feed.TenantID)
+	ctx = actor.Set(ctx, feed.OwnerID)
+
+	var filter appointment.QueryFilter
+	filter.WithOwnerID(feed.OwnerID)
+	filter.WithStartDate(time.Now().Add(-feedHistory))
+
+	orderBy := order.NewBy(appointment.OrderByStartsAt, order.ASC)
+
+	appts, err := h.appointment.Query(ctx, filter, orderBy, 1, feedLimit)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return web.RespondRaw(ctx, w, icalendar("Appointments", appts), "text/calendar; charset=utf-8", http.StatusOK)
+}
+
+// ========================================================================
+
+// queryAppointment returns the appointment named in the request as long as it
+// belongs to the user in the request.
+func (h *Handlers) queryAppointment(ctx context.Context, r *http.Request) (appointment.Appointment, error) {
+	appointmentID, err := uuid.Parse(web.Param(r, "appointment_id"))
+	if err != nil {
+		return appointment.Appointment{}, response.NewError(ErrInvalidID, http.StatusBadRequest)
+	}
+
+	appt, err := h.appointment.QueryByID(ctx, appointmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrNotFound):
+			return appointment.Appointment{}, response.NewError(appointment.ErrNotFound, http.StatusNotFound)
+		default:
+			return appointment.Appointment{}, fmt.Errorf("querybyid: id[%s]: %w", appointmentID, err)
+		}
+	}
+
+	if appt.OwnerID != auth.GetUserID(ctx) {
+		return appointment.Appointment{}, response.NewError(appointment.ErrNotFound, http.StatusNotFound)
+	}
+
+	return appt, nil
+}
+
+// toResponseError maps the errors the core returns for rejected appointments
+// to their trusted form. It returns nil for any other error.
+func toResponseError(err error) error {
+	switch {
+	case errors.Is(err, appointment.ErrOwnerNotFound):
+		return response.NewError(appointment.ErrOwnerNotFound, http.StatusNotFound)
+	case errors.Is(err, appointment.ErrConflict):
+		return response.NewError(appointment.ErrConflict, http.StatusConflict)
+	case errors.Is(err, appointment.ErrInvalidTimeRange):
+		return validate.NewFieldsError("endsAt", appointment.ErrInvalidTimeRange)
+	case errors.Is(err, appointment.ErrInvalidTimeZone):
+		return validate.NewFieldsError("timeZone", appointment.ErrInvalidTimeZone)
+	case errors.Is(err, appointment.ErrInvalidRecurrence):
+		return validate.NewFieldsError("recurrence", err)
+	}
+	return nil
+}
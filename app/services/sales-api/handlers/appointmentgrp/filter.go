@@ -0,0 +1,57 @@
+package appointmentgrp
+
+import (
+	"net/http"
+	"sales-api/business/core/appointment"
+	"sales-api/foundation/validate"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func parseFilter(r *http.Request) (appointment.QueryFilter, error) {
+	const (
+		filterByAppointmentID = "appointment_id"
+		filterByTitle         = "title"
+		filterByStartDate     = "start_date"
+		filterByEndDate       = "end_date"
+	)
+
+	values := r.URL.Query()
+
+	var filter appointment.QueryFilter
+
+	if appointmentID := values.Get(filterByAppointmentID); appointmentID != "" {
+		id, err := uuid.Parse(appointmentID)
+		if err != nil {
+			return appointment.QueryFilter{}, validate.NewFieldsError(filterByAppointmentID, err)
+		}
+		filter.WithAppointmentID(id)
+	}
+
+	if title := values.Get(filterByTitle); title != "" {
+		filter.WithTitle(title)
+	}
+
+	if startDate := values.Get(filterByStartDate); startDate != "" {
+		t, err := time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			return appointment.QueryFilter{}, validate.NewFieldsError(filterByStartDate, err)
+		}
+		filter.WithStartDate(t)
+	}
+
+	if endDate := values.Get(filterByEndDate); endDate != "" {
+		t, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			return appointment.QueryFilter{}, validate.NewFieldsError(filterByEndDate, err)
+		}
+		filter.WithEndDate(t)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return appointment.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
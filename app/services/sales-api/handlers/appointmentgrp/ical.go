@@ -0,0 +1,221 @@
+package appointmentgrp
+
+import (
+	"bytes"
+	"fmt"
+	"sales-api/business/core/appointment"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Layouts for the DATE-TIME values of RFC 5545.
+const (
+	icalUTCLayout   = "20060102T150405Z"
+	icalLocalLayout = "20060102T150405"
+)
+
+// maxLineOctets is the longest a content line may be before it is folded.
+const maxLineOctets = 75
+
+// zoneHorizon is how far past its first occurrence the time zone of an
+// appointment that recurs forever is defined.
+const zoneHorizon = 10 * 365 * 24 * time.Hour
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// icalendar encodes the appointments as an iCalendar (RFC 5545) document
+// that calendar apps can subscribe to.
+func icalendar(name string, appts []appointment.Appointment) []byte {
+	var b bytes.Buffer
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//sales-api//appointments//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+textEscaper.Replace(name))
+
+	for _, zone := range zones(appts) {
+		writeTimeZone(&b, zone)
+	}
+
+	for _, appt := range appts {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+appt.ID.String()+"@sales-api")
+		writeLine(&b, "DTSTAMP:"+appt.UpdatedAt.UTC().Format(icalUTCLayout))
+		writeLine(&b, "DTSTART"+icalDateTime(appt.StartsAt, appt.Loc()))
+		writeLine(&b, "DTEND"+icalDateTime(appt.EndsAt, appt.Loc()))
+		if !appt.Recurrence.IsZero() {
+			writeLine(&b, "RRULE:"+appt.Recurrence.String())
+		}
+		writeLine(&b, "SUMMARY:"+textEscaper.Replace(appt.Title))
+		if appt.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+textEscaper.Replace(appt.Description))
+		}
+		if appt.Location != "" {
+			writeLine(&b, "LOCATION:"+textEscaper.Replace(appt.Location))
+		}
+		for _, attendee := range appt.Attendees {
+			line := "ATTENDEE"
+			if attendee.Name != "" {
+				line += `;CN="` + strings.ReplaceAll(attendee.Name, `"`, "'") + `"`
+			}
+			writeLine(&b, line+":mailto:"+attendee.Address)
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+// icalDateTime returns the parameters and value of a DATE-TIME property. Times
+// outside of UTC carry the TZID so recurrences follow daylight saving; the
+// VTIMEZONE written by writeTimeZone defines it.
+func icalDateTime(t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return ":" + t.UTC().Format(icalUTCLayout)
+	}
+	return ";TZID=" + loc.String() + ":" + t.In(loc).Format(icalLocalLayout)
+}
+
+// zone is a time zone the feed refers to and the span of time it has to be
+// defined for.
+type zone struct {
+	loc  *time.Location
+	from time.Time
+	to   time.Time
+}
+
+// zones returns the time zones, other than UTC, that the appointments are
+// scheduled in, each spanning every occurrence scheduled in it.
+func zones(appts []appointment.Appointment) []zone {
+	var zs []zone
+	for _, appt := range appts {
+		loc := appt.Loc()
+		if loc == time.UTC {
+			continue
+		}
+
+		to, ok := appt.SeriesEndsAt()
+		if !ok {
+			to = appt.EndsAt.Add(zoneHorizon)
+		}
+
+		i := slices.IndexFunc(zs, func(z zone) bool { return z.loc.String() == loc.String() })
+		if i == -1 {
+			zs = append(zs, zone{loc: loc, from: appt.StartsAt, to: to})
+			continue
+		}
+		if appt.StartsAt.Before(zs[i].from) {
+			zs[i].from = appt.StartsAt
+		}
+		if to.After(zs[i].to) {
+			zs[i].to = to
+		}
+	}
+	return zs
+}
+
+// writeTimeZone writes the VTIMEZONE component the TZID of a DATE-TIME
+// refers to. The offset in effect at the start of the span is the first
+// observance, every change of offset within the span is another.
+func writeTimeZone(b *bytes.Buffer, z zone) {
+	writeLine(b, "BEGIN:VTIMEZONE")
+	writeLine(b, "TZID:"+z.loc.String())
+
+	start := z.from.In(z.loc)
+	_, offset := start.Zone()
+	writeObservance(b, start, offset)
+
+	// Offsets change at most a few times a year, so stepping a day at a time
+	// can't skip one.
+	for t := start; t.Before(z.to); {
+		next := t.Add(24 * time.Hour)
+		if _, o := next.Zone(); o != offset {
+			at := transition(t, next)
+			writeObservance(b, at, offset)
+			_, offset = at.Zone()
+		}
+		t = next
+	}
+
+	writeLine(b, "END:VTIMEZONE")
+}
+
+// writeObservance writes the STANDARD or DAYLIGHT component for the offset
+// that takes effect at t. Its start is the local time in the offset it
+// replaces.
+func writeObservance(b *bytes.Buffer, t time.Time, fromOffset int) {
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, offset := t.Zone()
+
+	writeLine(b, "BEGIN:"+kind)
+	writeLine(b, "DTSTART:"+t.UTC().Add(time.Duration(fromOffset)*time.Second).Format(icalLocalLayout))
+	writeLine(b, "TZOFFSETFROM:"+utcOffset(fromOffset))
+	writeLine(b, "TZOFFSETTO:"+utcOffset(offset))
+	writeLine(b, "TZNAME:"+textEscaper.Replace(name))
+	writeLine(b, "END:"+kind)
+}
+
+// transition returns the first second after before with the offset in
+// effect at after.
+func transition(before time.Time, after time.Time) time.Time {
+	_, offset := before.Zone()
+	for after.Sub(before) > time.Second {
+		mid := before.Add(after.Sub(before) / 2)
+		if _, o := mid.Zone(); o == offset {
+			before = mid
+			continue
+		}
+		after = mid
+	}
+	return after
+}
+
+// utcOffset formats an offset east of UTC in seconds as a UTC-OFFSET value.
+func utcOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+
+	s := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
+
+// writeLine writes a content line, folding it so no line is longer than
+// 75 octets without splitting a multi-byte character.
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+
+		// Continuation lines lose one octet to the leading space.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
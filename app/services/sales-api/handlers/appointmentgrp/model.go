@@ -0,0 +1,210 @@
+package appointmentgrp
+
+import (
+	"fmt"
+	"net/mail"
+	"sales-api/business/core/appointment"
+	"sales-api/foundation/validate"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AppAppointment represents information about an individual appointment.
+type AppAppointment struct {
+	ID          string   `json:"id"`
+	OwnerID     string   `json:"ownerId"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	Attendees   []string `json:"attendees"`
+	TimeZone    string   `json:"timeZone"`
+	StartsAt    string   `json:"startsAt"`
+	EndsAt      string   `json:"endsAt"`
+	Recurrence  string   `json:"recurrence"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+func toAppAppointment(appt appointment.Appointment) AppAppointment {
+	attendees := make([]string, len(appt.Attendees))
+	for i, addr := range appt.Attendees {
+		attendees[i] = addr.String()
+	}
+
+	loc := appt.Loc()
+
+	return AppAppointment{
+		ID:          appt.ID.String(),
+		OwnerID:     appt.OwnerID.String(),
+		Title:       appt.Title,
+		Description: appt.Description,
+		Location:    appt.Location,
+		Attendees:   attendees,
+		TimeZone:    appt.TimeZone,
+		StartsAt:    appt.StartsAt.In(loc).Format(time.RFC3339),
+		EndsAt:      appt.EndsAt.In(loc).Format(time.RFC3339),
+		Recurrence:  appt.Recurrence.String(),
+		CreatedAt:   appt.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   appt.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toAppAppointments(appts []appointment.Appointment) []AppAppointment {
+	items := make([]AppAppointment, len(appts))
+	for i, appt := range appts {
+		items[i] = toAppAppointment(appt)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// AppNewAppointment contains information needed to create a new appointment.
+type AppNewAppointment struct {
+	Title       string   `json:"title" validate:"required"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	Attendees   []string `json:"attendees"`
+	TimeZone    string   `json:"timeZone" validate:"required"`
+	StartsAt    string   `json:"startsAt" validate:"required"`
+	EndsAt      string   `json:"endsAt" validate:"required"`
+	Recurrence  string   `json:"recurrence"`
+}
+
+func toCoreNewAppointment(ownerID uuid.UUID, app AppNewAppointment) (appointment.NewAppointment, error) {
+	attendees, err := parseAttendees(app.Attendees)
+	if err != nil {
+		return appointment.NewAppointment{}, err
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, app.StartsAt)
+	if err != nil {
+		return appointment.NewAppointment{}, validate.NewFieldsError("startsAt", err)
+	}
+
+	endsAt, err := time.Parse(time.RFC3339, app.EndsAt)
+	if err != nil {
+		return appointment.NewAppointment{}, validate.NewFieldsError("endsAt", err)
+	}
+
+	recurrence, err := appointment.ParseRecurrence(app.Recurrence)
+	if err != nil {
+		return appointment.NewAppointment{}, validate.NewFieldsError("recurrence", err)
+	}
+
+	na := appointment.NewAppointment{
+		OwnerID:     ownerID,
+		Title:       app.Title,
+		Description: app.Description,
+		Location:    app.Location,
+		Attendees:   attendees,
+		TimeZone:    app.TimeZone,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Recurrence:  recurrence,
+	}
+
+	return na, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewAppointment) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// =============================================================================
+
+// AppUpdateAppointment contains information needed to update an appointment.
+type AppUpdateAppointment struct {
+	Title       *string  `json:"title" validate:"omitempty,min=1"`
+	Description *string  `json:"description"`
+	Location    *string  `json:"location"`
+	Attendees   []string `json:"attendees"`
+	TimeZone    *string  `json:"timeZone"`
+	StartsAt    *string  `json:"startsAt"`
+	EndsAt      *string  `json:"endsAt"`
+	Recurrence  *string  `json:"recurrence"`
+}
+
+func toCoreUpdateAppointment(app AppUpdateAppointment) (appointment.UpdateAppointment, error) {
+	var attendees []mail.Address
+	if app.Attendees != nil {
+		var err error
+		attendees, err = parseAttendees(app.Attendees)
+		if err != nil {
+			return appointment.UpdateAppointment{}, err
+		}
+	}
+
+	var startsAt *time.Time
+	if app.StartsAt != nil {
+		t, err := time.Parse(time.RFC3339, *app.StartsAt)
+		if err != nil {
+			return appointment.UpdateAppointment{}, validate.NewFieldsError("startsAt", err)
+		}
+		startsAt = &t
+	}
+
+	var endsAt *time.Time
+	if app.EndsAt != nil {
+		t, err := time.Parse(time.RFC3339, *app.EndsAt)
+		if err != nil {
+			return appointment.UpdateAppointment{}, validate.NewFieldsError("endsAt", err)
+		}
+		endsAt = &t
+	}
+
+	var recurrence *appointment.Recurrence
+	if app.Recurrence != nil {
+		r, err := appointment.ParseRecurrence(*app.Recurrence)
+		if err != nil {
+			return appointment.UpdateAppointment{}, validate.NewFieldsError("recurrence", err)
+		}
+		recurrence = &r
+	}
+
+	ua := appointment.UpdateAppointment{
+		Title:       app.Title,
+		Description: app.Description,
+		Location:    app.Location,
+		Attendees:   attendees,
+		TimeZone:    app.TimeZone,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Recurrence:  recurrence,
+	}
+
+	return ua, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppUpdateAppointment) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+
+// AppFeed contains the address a calendar app subscribes to.
+type AppFeed struct {
+	URL string `json:"url"`
+}
+
+func parseAttendees(values []string) ([]mail.Address, error) {
+	attendees := make([]mail.Address, len(values))
+	for i, value := range values {
+		addr, err := mail.ParseAddress(value)
+		if err != nil {
+			return nil, validate.NewFieldsError("attendees", fmt.Errorf("invalid attendee %q", value))
+		}
+		attendees[i] = *addr
+	}
+	return attendees, nil
+}
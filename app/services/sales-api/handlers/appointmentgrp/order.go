@@ -0,0 +1,37 @@
+package appointmentgrp
+
+import (
+	"errors"
+	"net/http"
+	"sales-api/business/core/appointment"
+	"sales-api/business/data/order"
+	"sales-api/foundation/validate"
+)
+
+func parseOrder(r *http.Request) (order.By, error) {
+	const (
+		orderByID       = "appointment_id"
+		orderByTitle    = "title"
+		orderByStartsAt = "starts_at"
+		orderByEndsAt   = "ends_at"
+	)
+	var orderByFields = map[string]string{
+		orderByID:       appointment.OrderByID,
+		orderByTitle:    appointment.OrderByTitle,
+		orderByStartsAt: appointment.OrderByStartsAt,
+		orderByEndsAt:   appointment.OrderByEndsAt,
+	}
+
+	orderBy, err := order.Parse(r, order.NewBy(orderByStartsAt, order.ASC))
+	if err != nil {
+		return order.By{}, err
+	}
+
+	if _, exists := orderByFields[orderBy.Field]; !exists {
+		return order.By{}, validate.NewFieldsError(orderBy.Field, errors.New("order field does not exist"))
+	}
+
+	orderBy.Field = orderByFields[orderBy.Field]
+
+	return orderBy, nil
+}
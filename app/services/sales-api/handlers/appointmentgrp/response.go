@@ -0,0 +1,16 @@
+package appointmentgrp
+
+import (
+	"sales-api/business/core/appointment"
+	"sales-api/business/web/v1/response"
+)
+
+type appointmentRes struct {
+	Appointment AppAppointment `json:"appointment"`
+}
+
+func appointmentResponse(appt appointment.Appointment) response.Success[appointmentRes] {
+	return response.NewSuccess(appointmentRes{
+		Appointment: toAppAppointment(appt),
+	})
+}
@@ -0,0 +1,54 @@
+package appointmentgrp
+
+import (
+	"net/url"
+	"sales-api/business/core/appointment"
+	"sales-api/business/core/appointment/stores/appointmentdb"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/mid"
+	"sales-api/foundation/logger"
+	"sales-api/foundation/web"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build         string
+	Log           *logger.Logger
+	DB            *sqlx.DB
+	MaintenanceDB *sqlx.DB
+	Auth          *auth.Auth
+	PublicURL     *url.URL
+}
+
+func Route(app *web.App, cfg Config) {
+
+	apptCore := appointment.NewCore(cfg.Log, appointmentdb.NewRepository(cfg.Log, cfg.DB))
+
+	// Calendar feeds are looked up by their token before the tenant is known.
+	feedCore := appointment.NewCore(cfg.Log, appointmentdb.NewRepository(cfg.Log, cfg.MaintenanceDB))
+
+	authMid := mid.Authenticate(cfg.Auth)
+	ruleAdminOrSubject := mid.Authorize(cfg.Auth, auth.RuleAdminOrSubject)
+	ruleAdminOrManager := mid.Authorize(cfg.Auth, auth.RuleAdminOrSubjectOrManager)
+
+	tran := mid.ExecuteInTransaction(cfg.Log, pgx.NewBeginner(cfg.DB))
+
+	hdl := New(apptCore, feedCore, cfg.PublicURL)
+	// POST===========================================================================
+	app.HandleFunc("/users/{user_id}/appointments", hdl.Create, authMid, ruleAdminOrSubject, tran).Methods("POST")
+	app.HandleFunc("/users/{user_id}/appointments/feed", hdl.RotateFeed, authMid, ruleAdminOrSubject).Methods("POST")
+
+	// PUT===========================================================================
+	app.HandleFunc("/users/{user_id}/appointments/{appointment_id}", hdl.UpdateByID, authMid, ruleAdminOrSubject, tran).Methods("PUT")
+
+	// GET===========================================================================
+	app.HandleFunc("/users/{user_id}/appointments/{appointment_id}", hdl.QueryByID, authMid, ruleAdminOrManager).Methods("GET")
+	app.HandleFunc("/users/{user_id}/appointments", hdl.Query, authMid, ruleAdminOrManager).Methods("GET")
+	app.HandleFunc("/appointments/feed/{token:[A-Za-z0-9_-]+}.ics", hdl.Feed).Methods("GET")
+
+	// DELETE===========================================================================
+	app.HandleFunc("/users/{user_id}/appointments/{appointment_id}", hdl.DeleteByID, authMid, ruleAdminOrSubject).Methods("DELETE")
+
+}
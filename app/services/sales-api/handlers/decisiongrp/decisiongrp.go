@@ -0,0 +1,54 @@
+package decisiongrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sales-api/business/core/decision"
+	"sales-api/business/data/page"
+	"sales-api/business/web/v1/response"
+	"sales-api/foundation/web"
+)
+
+// Handlers manages the set of authorization decision endpoints.
+type Handlers struct {
+	decision *decision.Core
+}
+
+// New constructs a handlers for route access.
+func New(decision *decision.Core) *Handlers {
+	return &Handlers{
+		decision: decision,
+	}
+}
+
+// Query returns a list of authorization decisions with paging, the latest
+// first unless asked otherwise.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := page.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	decisions, err := h.decision.Query(ctx, filter, orderBy, page.Page, page.PageSize)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	total, err := h.decision.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewPageDocument(toAppDecisions(decisions), total, page.Page, page.PageSize), http.StatusOK)
+}
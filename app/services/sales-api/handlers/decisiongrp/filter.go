@@ -0,0 +1,72 @@
+package decisiongrp
+
+import (
+	"net/http"
+	"sales-api/business/core/decision"
+	"sales-api/foundation/validate"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func parseFilter(r *http.Request) (decision.QueryFilter, error) {
+	const (
+		filterBySubject          = "subject"
+		filterByRule             = "rule"
+		filterByUserID           = "user_id"
+		filterByAllowed          = "allowed"
+		filterByStartCreatedDate = "start_created_date"
+		filterByEndCreatedDate   = "end_created_date"
+	)
+
+	values := r.URL.Query()
+
+	var filter decision.QueryFilter
+
+	if subject := values.Get(filterBySubject); subject != "" {
+		filter.WithSubject(subject)
+	}
+
+	if rule := values.Get(filterByRule); rule != "" {
+		filter.WithRule(rule)
+	}
+
+	if userID := values.Get(filterByUserID); userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return decision.QueryFilter{}, validate.NewFieldsError(filterByUserID, err)
+		}
+		filter.WithUserID(id)
+	}
+
+	if allowed := values.Get(filterByAllowed); allowed != "" {
+		b, err := strconv.ParseBool(allowed)
+		if err != nil {
+			return decision.QueryFilter{}, validate.NewFieldsError(filterByAllowed, err)
+		}
+		filter.WithAllowed(b)
+	}
+
+	if createdDate := values.Get(filterByStartCreatedDate); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return decision.QueryFilter{}, validate.NewFieldsError(filterByStartCreatedDate, err)
+		}
+		filter.WithStartDateCreated(t)
+	}
+
+	if createdDate := values.Get(filterByEndCreatedDate); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return decision.QueryFilter{}, validate.NewFieldsError(filterByEndCreatedDate, err)
+		}
+		filter.WithEndCreatedDate(t)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return decision.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,51 @@
+package decisiongrp
+
+import (
+	"sales-api/business/core/decision"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AppDecision represents an authorization decision. The latency is in
+// microseconds.
+type AppDecision struct {
+	ID            string `json:"id"`
+	Subject       string `json:"subject"`
+	Rule          string `json:"rule"`
+	UserID        string `json:"userId,omitempty"`
+	Route         string `json:"route"`
+	Allowed       bool   `json:"allowed"`
+	Reason        string `json:"reason,omitempty"`
+	PolicyVersion string `json:"policyVersion"`
+	LatencyUS     int64  `json:"latencyUs"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+func toAppDecision(d decision.Decision) AppDecision {
+	app := AppDecision{
+		ID:            d.ID.String(),
+		Subject:       d.Subject,
+		Rule:          d.Rule,
+		Route:         d.Route,
+		Allowed:       d.Allowed,
+		Reason:        d.Reason,
+		PolicyVersion: d.PolicyVersion,
+		LatencyUS:     d.Latency.Microseconds(),
+		CreatedAt:     d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.UserID != uuid.Nil {
+		app.UserID = d.UserID.String()
+	}
+
+	return app
+}
+
+func toAppDecisions(ds []decision.Decision) []AppDecision {
+	items := make([]AppDecision, len(ds))
+	for i, d := range ds {
+		items[i] = toAppDecision(d)
+	}
+
+	return items
+}
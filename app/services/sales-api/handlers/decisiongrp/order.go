@@ -0,0 +1,37 @@
+package decisiongrp
+
+import (
+	"errors"
+	"net/http"
+	"sales-api/business/core/decision"
+	"sales-api/business/data/order"
+	"sales-api/foundation/validate"
+)
+
+func parseOrder(r *http.Request) (order.By, error) {
+	const (
+		orderByCreatedAt = "created_at"
+		orderBySubject   = "subject"
+		orderByRule      = "rule"
+		orderByLatency   = "latency"
+	)
+	var orderByFields = map[string]string{
+		orderByCreatedAt: decision.OrderByCreatedAt,
+		orderBySubject:   decision.OrderBySubject,
+		orderByRule:      decision.OrderByRule,
+		orderByLatency:   decision.OrderByLatency,
+	}
+
+	orderBy, err := order.Parse(r, order.NewBy(orderByCreatedAt, order.DESC))
+	if err != nil {
+		return order.By{}, err
+	}
+
+	if _, exists := orderByFields[orderBy.Field]; !exists {
+		return order.By{}, validate.NewFieldsError(orderBy.Field, errors.New("order field does not exist"))
+	}
+
+	orderBy.Field = orderByFields[orderBy.Field]
+
+	return orderBy, nil
+}
@@ -0,0 +1,32 @@
+package decisiongrp
+
+import (
+	"sales-api/business/core/decision"
+	"sales-api/business/core/decision/stores/decisiondb"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/mid"
+	"sales-api/foundation/logger"
+	"sales-api/foundation/web"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+func Route(app *web.App, cfg Config) {
+
+	decCore := decision.NewCore(cfg.Log, decisiondb.NewRepository(cfg.Log, cfg.DB))
+
+	authMid := mid.Authenticate(cfg.Auth)
+	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
+
+	hdl := New(decCore)
+	// GET===========================================================================
+	app.HandleFunc("/decisions", hdl.Query, authMid, ruleAdmin).Methods("GET")
+
+}
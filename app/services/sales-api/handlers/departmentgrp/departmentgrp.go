@@ -0,0 +1,209 @@
+package departmentgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sales-api/business/core/department"
+	"sales-api/business/data/page"
+	"sales-api/business/data/transaction"
+	"sales-api/business/web/v1/response"
+	"sales-api/foundation/validate"
+	"sales-api/foundation/web"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for handling department group errors.
+var (
+	ErrInvalidID = errors.New("ID is not in its proper form")
+)
+
+// Handlers manages the set of department endpoints.
+type Handlers struct {
+	department *department.Core
+}
+
+// New constructs a handlers for route access.
+func New(department *department.Core) *Handlers {
+	return &Handlers{
+		department: department,
+	}
+}
+
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		department, err := h.department.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+		h = &Handlers{
+			department: department,
+		}
+		return h, nil
+	}
+	return h, nil
+}
+
+// Create adds a new department to the system.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppNewDepartment
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	nd, err := toCoreNewDepartment(app)
+	if err != nil {
+		return err
+	}
+
+	dep, err := h.department.Create(ctx, nd)
+	if err != nil {
+		if rerr := toResponseError(err); rerr != nil {
+			return rerr
+		}
+		return fmt.Errorf("create: dep[%+v]: %w", nd, err)
+	}
+
+	return web.Respond(ctx, w, departmentResponse(dep), http.StatusCreated)
+}
+
+// UpdateByID updates a department by its ID.
+func (h *Handlers) UpdateByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppUpdateDepartment
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	dep, err := h.queryDepartment(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	ud, err := toCoreUpdateDepartment(app)
+	if err != nil {
+		return err
+	}
+
+	dep, err = h.department.Update(ctx, dep, ud)
+	if err != nil {
+		if rerr := toResponseError(err); rerr != nil {
+			return rerr
+		}
+		return fmt.Errorf("update: departmentID[%s] ud[%+v]: %w", dep.ID, ud, err)
+	}
+
+	return web.Respond(ctx, w, departmentResponse(dep), http.StatusOK)
+}
+
+// DeleteByID removes a department by its ID.
+func (h *Handlers) DeleteByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	dep, err := h.queryDepartment(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.department.Delete(ctx, dep.ID); err != nil {
+		return fmt.Errorf("delete: departmentID[%s]: %w", dep.ID, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryByID returns a department by its ID.
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	dep, err := h.queryDepartment(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, departmentResponse(dep), http.StatusOK)
+}
+
+// Query returns a list of departments with paging.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := page.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	deps, err := h.department.Query(ctx, filter, orderBy, page.Page, page.PageSize)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	total, err := h.department.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewPageDocument(toAppDepartments(deps), total, page.Page, page.PageSize), http.StatusOK)
+}
+
+// OrgChart returns the departments as a tree along with the users in each.
+func (h *Handlers) OrgChart(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	nodes, err := h.department.OrgChart(ctx)
+	if err != nil {
+		return fmt.Errorf("orgchart: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewSuccess(toAppNodes(nodes)), http.StatusOK)
+}
+
+// ========================================================================
+
+func (h *Handlers) queryDepartment(ctx context.Context, r *http.Request) (department.Department, error) {
+	departmentID, err := uuid.Parse(web.Param(r, "department_id"))
+	if err != nil {
+		return department.Department{}, response.NewError(ErrInvalidID, http.StatusBadRequest)
+	}
+
+	dep, err := h.department.QueryByID(ctx, departmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, department.ErrNotFound):
+			return department.Department{}, response.NewError(department.ErrNotFound, http.StatusNotFound)
+		default:
+			return department.Department{}, fmt.Errorf("querybyid: id[%s]: %w", departmentID, err)
+		}
+	}
+
+	return dep, nil
+}
+
+// toResponseError maps the errors the core returns for rejected departments
+// to their trusted form. It returns nil for any other error.
+func toResponseError(err error) error {
+	switch {
+	case errors.Is(err, department.ErrUniqueName):
+		return response.NewError(department.ErrUniqueName, http.StatusConflict)
+	case errors.Is(err, department.ErrManagerNotFound):
+		return validate.NewFieldsError("managerId", department.ErrManagerNotFound)
+	case errors.Is(err, department.ErrParentNotFound):
+		return validate.NewFieldsError("parentId", department.ErrParentNotFound)
+	case errors.Is(err, department.ErrCycle):
+		return validate.NewFieldsError("parentId", department.ErrCycle)
+	}
+	return nil
+}
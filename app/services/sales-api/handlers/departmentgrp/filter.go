@@ -0,0 +1,56 @@
+package departmentgrp
+
+import (
+	"net/http"
+	"sales-api/business/core/department"
+	"sales-api/foundation/validate"
+
+	"github.com/google/uuid"
+)
+
+func parseFilter(r *http.Request) (department.QueryFilter, error) {
+	const (
+		filterByDepartmentID = "department_id"
+		filterByName         = "name"
+		filterByManagerID    = "manager_id"
+		filterByParentID     = "parent_id"
+	)
+
+	values := r.URL.Query()
+
+	var filter department.QueryFilter
+
+	if departmentID := values.Get(filterByDepartmentID); departmentID != "" {
+		id, err := uuid.Parse(departmentID)
+		if err != nil {
+			return department.QueryFilter{}, validate.NewFieldsError(filterByDepartmentID, err)
+		}
+		filter.WithDepartmentID(id)
+	}
+
+	if name := values.Get(filterByName); name != "" {
+		filter.WithName(name)
+	}
+
+	if managerID := values.Get(filterByManagerID); managerID != "" {
+		id, err := uuid.Parse(managerID)
+		if err != nil {
+			return department.QueryFilter{}, validate.NewFieldsError(filterByManagerID, err)
+		}
+		filter.WithManagerID(id)
+	}
+
+	if parentID := values.Get(filterByParentID); parentID != "" {
+		id, err := uuid.Parse(parentID)
+		if err != nil {
+			return department.QueryFilter{}, validate.NewFieldsError(filterByParentID, err)
+		}
+		filter.WithParentID(id)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return department.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
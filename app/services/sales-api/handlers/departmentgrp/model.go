@@ -0,0 +1,186 @@
+package departmentgrp
+
+import (
+	"fmt"
+	"sales-api/business/core/department"
+	"sales-api/foundation/validate"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AppDepartment represents information about an individual department.
+type AppDepartment struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ManagerID string `json:"managerId"`
+	ParentID  string `json:"parentId"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+func toAppDepartment(dep department.Department) AppDepartment {
+	return AppDepartment{
+		ID:        dep.ID.String(),
+		Name:      dep.Name,
+		ManagerID: optionalID(dep.ManagerID),
+		ParentID:  optionalID(dep.ParentID),
+		CreatedAt: dep.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: dep.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toAppDepartments(deps []department.Department) []AppDepartment {
+	items := make([]AppDepartment, len(deps))
+	for i, dep := range deps {
+		items[i] = toAppDepartment(dep)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// AppNewDepartment contains information needed to create a new department.
+type AppNewDepartment struct {
+	Name      string `json:"name" validate:"required"`
+	ManagerID string `json:"managerId" validate:"omitempty,uuid"`
+	ParentID  string `json:"parentId" validate:"omitempty,uuid"`
+}
+
+func toCoreNewDepartment(app AppNewDepartment) (department.NewDepartment, error) {
+	managerID, err := parseOptionalID("managerId", app.ManagerID)
+	if err != nil {
+		return department.NewDepartment{}, err
+	}
+
+	parentID, err := parseOptionalID("parentId", app.ParentID)
+	if err != nil {
+		return department.NewDepartment{}, err
+	}
+
+	nd := department.NewDepartment{
+		Name:      app.Name,
+		ManagerID: managerID,
+		ParentID:  parentID,
+	}
+
+	return nd, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewDepartment) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// =============================================================================
+
+// AppUpdateDepartment contains information needed to update a department. An
+// empty managerId or parentId removes the manager or parent.
+type AppUpdateDepartment struct {
+	Name      *string `json:"name" validate:"omitempty,min=1"`
+	ManagerID *string `json:"managerId"`
+	ParentID  *string `json:"parentId"`
+}
+
+func toCoreUpdateDepartment(app AppUpdateDepartment) (department.UpdateDepartment, error) {
+	var managerID *uuid.UUID
+	if app.ManagerID != nil {
+		id, err := parseOptionalID("managerId", *app.ManagerID)
+		if err != nil {
+			return department.UpdateDepartment{}, err
+		}
+		managerID = &id
+	}
+
+	var parentID *uuid.UUID
+	if app.ParentID != nil {
+		id, err := parseOptionalID("parentId", *app.ParentID)
+		if err != nil {
+			return department.UpdateDepartment{}, err
+		}
+		parentID = &id
+	}
+
+	ud := department.UpdateDepartment{
+		Name:      app.Name,
+		ManagerID: managerID,
+		ParentID:  parentID,
+	}
+
+	return ud, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppUpdateDepartment) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+
+// AppMember represents a user as they appear in the org chart.
+type AppMember struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AppNode represents a department in the org chart.
+type AppNode struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	ManagerID string      `json:"managerId"`
+	Members   []AppMember `json:"members"`
+	Children  []AppNode   `json:"children"`
+}
+
+func toAppNodes(nodes []department.Node) []AppNode {
+	items := make([]AppNode, len(nodes))
+	for i, node := range nodes {
+		members := make([]AppMember, len(node.Members))
+		for j, mem := range node.Members {
+			members[j] = AppMember{
+				ID:   mem.UserID.String(),
+				Name: mem.Name,
+			}
+		}
+
+		items[i] = AppNode{
+			ID:        node.Department.ID.String(),
+			Name:      node.Department.Name,
+			ManagerID: optionalID(node.Department.ManagerID),
+			Members:   members,
+			Children:  toAppNodes(node.Children),
+		}
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// optionalID returns the string form of an id, empty when it isn't set.
+func optionalID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// parseOptionalID parses an id that may be left empty.
+func parseOptionalID(field string, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, validate.NewFieldsError(field, fmt.Errorf("invalid id %q", value))
+	}
+	return id, nil
+}
@@ -0,0 +1,33 @@
+package departmentgrp
+
+import (
+	"errors"
+	"net/http"
+	"sales-api/business/core/department"
+	"sales-api/business/data/order"
+	"sales-api/foundation/validate"
+)
+
+func parseOrder(r *http.Request) (order.By, error) {
+	const (
+		orderByID   = "department_id"
+		orderByName = "name"
+	)
+	var orderByFields = map[string]string{
+		orderByID:   department.OrderByID,
+		orderByName: department.OrderByName,
+	}
+
+	orderBy, err := order.Parse(r, order.NewBy(orderByName, order.ASC))
+	if err != nil {
+		return order.By{}, err
+	}
+
+	if _, exists := orderByFields[orderBy.Field]; !exists {
+		return order.By{}, validate.NewFieldsError(orderBy.Field, errors.New("order field does not exist"))
+	}
+
+	orderBy.Field = orderByFields[orderBy.Field]
+
+	return orderBy, nil
+}
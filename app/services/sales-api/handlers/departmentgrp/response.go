@@ -0,0 +1,16 @@
+package departmentgrp
+
+import (
+	"sales-api/business/core/department"
+	"sales-api/business/web/v1/response"
+)
+
+type departmentRes struct {
+	Department AppDepartment `json:"department"`
+}
+
+func departmentResponse(dep department.Department) response.Success[departmentRes] {
+	return response.NewSuccess(departmentRes{
+		Department: toAppDepartment(dep),
+	})
+}
@@ -0,0 +1,51 @@
+package departmentgrp
+
+import (
+	"sales-api/business/core/department"
+	"sales-api/business/core/department/stores/departmentdb"
+	"sales-api/business/core/user"
+	"sales-api/business/core/user/stores/userdb"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/mid"
+	"sales-api/foundation/logger"
+	"sales-api/foundation/web"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build     string
+	Log       *logger.Logger
+	DB        *sqlx.DB
+	Auth      *auth.Auth
+	UserCache *user.EnabledCache
+}
+
+func Route(app *web.App, cfg Config) {
+
+	usrCore := user.NewCore(cfg.Log, cfg.UserCache, userdb.NewRepository(cfg.Log, cfg.DB))
+	depCore := department.NewCore(cfg.Log, usrCore, departmentdb.NewRepository(cfg.Log, cfg.DB))
+
+	authMid := mid.Authenticate(cfg.Auth)
+	ruleAny := mid.Authorize(cfg.Auth, auth.RuleAny)
+	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
+
+	tran := mid.ExecuteInTransaction(cfg.Log, pgx.NewBeginner(cfg.DB))
+
+	hdl := New(depCore)
+	// POST===========================================================================
+	app.HandleFunc("/departments", hdl.Create, authMid, ruleAdmin, tran).Methods("POST")
+
+	// PUT===========================================================================
+	app.HandleFunc("/departments/{department_id}", hdl.UpdateByID, authMid, ruleAdmin, tran).Methods("PUT")
+
+	// GET===========================================================================
+	app.HandleFunc("/departments/chart", hdl.OrgChart, authMid, ruleAny).Methods("GET")
+	app.HandleFunc("/departments/{department_id}", hdl.QueryByID, authMid, ruleAny).Methods("GET")
+	app.HandleFunc("/departments", hdl.Query, authMid, ruleAny).Methods("GET")
+
+	// DELETE===========================================================================
+	app.HandleFunc("/departments/{department_id}", hdl.DeleteByID, authMid, ruleAdmin).Methods("DELETE")
+
+}
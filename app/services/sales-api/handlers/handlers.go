@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"sales-api/app/services/sales-api/handlers/appointmentgrp"
 	"sales-api/app/services/sales-api/handlers/checkgrp"
+	"sales-api/app/services/sales-api/handlers/decisiongrp"
+	"sales-api/app/services/sales-api/handlers/departmentgrp"
+	"sales-api/app/services/sales-api/handlers/jwksgrp"
+	"sales-api/app/services/sales-api/handlers/organizationgrp"
+	"sales-api/app/services/sales-api/handlers/rolegrp"
 	"sales-api/app/services/sales-api/handlers/usergrp"
 	v1 "sales-api/business/web/v1"
 	"sales-api/foundation/web"
@@ -18,7 +24,49 @@ type add struct{}
 func (a *add) Add(app *web.App, cfg v1.APIMuxConfig) {
 
 	checkgrp.Route(app, checkgrp.Config{Build: cfg.Build, Logger: cfg.Log, DB: cfg.DB})
+	jwksgrp.Route(app, jwksgrp.Config{
+		Build: cfg.Build,
+		Log:   cfg.Log,
+		Keys:  cfg.Keys,
+	})
 	usergrp.Route(app, usergrp.Config{
+		Build:         cfg.Build,
+		Log:           cfg.Log,
+		DB:            cfg.DB,
+		MaintenanceDB: cfg.MaintenanceDB,
+		Auth:          cfg.Auth,
+		UserCache:     cfg.UserCache,
+		RoleCache:     cfg.RoleCache,
+	})
+	organizationgrp.Route(app, organizationgrp.Config{
+		Build: cfg.Build,
+		Log:   cfg.Log,
+		DB:    cfg.DB,
+		Auth:  cfg.Auth,
+	})
+	rolegrp.Route(app, rolegrp.Config{
+		Build:     cfg.Build,
+		Log:       cfg.Log,
+		DB:        cfg.DB,
+		Auth:      cfg.Auth,
+		RoleCache: cfg.RoleCache,
+	})
+	departmentgrp.Route(app, departmentgrp.Config{
+		Build:     cfg.Build,
+		Log:       cfg.Log,
+		DB:        cfg.DB,
+		Auth:      cfg.Auth,
+		UserCache: cfg.UserCache,
+	})
+	appointmentgrp.Route(app, appointmentgrp.Config{
+		Build:         cfg.Build,
+		Log:           cfg.Log,
+		DB:            cfg.DB,
+		MaintenanceDB: cfg.MaintenanceDB,
+		Auth:          cfg.Auth,
+		PublicURL:     cfg.PublicURL,
+	})
+	decisiongrp.Route(app, decisiongrp.Config{
 		Build: cfg.Build,
 		Log:   cfg.Log,
 		DB:    cfg.DB,
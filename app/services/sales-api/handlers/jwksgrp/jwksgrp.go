@@ -0,0 +1,52 @@
+// Package jwksgrp publishes the public keys that verify the tokens this
+// service signs, as a JSON Web Key Set.
+package jwksgrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sales-api/business/web/v1/auth"
+	"sales-api/foundation/web"
+)
+
+// cacheMaxAge is how long clients may keep the key set. A new key has to be
+// published for at least this long before it signs anything.
+const cacheMaxAge = 300
+
+// Handlers manages the set of key endpoints.
+type Handlers struct {
+	keys auth.KeySet
+}
+
+// New constructs a handlers for route access.
+func New(keys auth.KeySet) *Handlers {
+	return &Handlers{
+		keys: keys,
+	}
+}
+
+// Query returns the public keys of every key that isn't retired.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	kids := h.keys.ActiveKIDs()
+
+	set := AppKeySet{
+		Keys: make([]AppKey, 0, len(kids)),
+	}
+	for _, kid := range kids {
+		pem, err := h.keys.PublicKey(kid)
+		if err != nil {
+			return fmt.Errorf("publickey: kid[%s]: %w", kid, err)
+		}
+
+		key, err := toAppKey(kid, pem)
+		if err != nil {
+			return fmt.Errorf("tojwk: kid[%s]: %w", kid, err)
+		}
+		set.Keys = append(set.Keys, key)
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", cacheMaxAge))
+
+	return web.Respond(ctx, w, set, http.StatusOK)
+}
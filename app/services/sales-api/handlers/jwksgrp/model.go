@@ -0,0 +1,92 @@
+package jwksgrp
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// AppKeySet represents a JSON Web Key Set as described by RFC 7517.
+type AppKeySet struct {
+	Keys []AppKey `json:"keys"`
+}
+
+// AppKey represents the public part of one signing key. Which parameters
+// are set depends on the key type.
+type AppKey struct {
+	KID string `json:"kid"`
+	KTY string `json:"kty"`
+	ALG string `json:"alg"`
+	USE string `json:"use"`
+	CRV string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+func toAppKey(kid string, publicPEM string) (AppKey, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return AppKey{}, errors.New("no pem block")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return AppKey{}, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return AppKey{
+			KID: kid,
+			KTY: "RSA",
+			ALG: "RS256",
+			USE: "sig",
+			N:   encode(pub.N),
+			E:   encode(big.NewInt(int64(pub.E))),
+		}, nil
+
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return AppKey{}, fmt.Errorf("unsupported curve %s", pub.Curve.Params().Name)
+		}
+
+		// Coordinates are padded to the size of the curve.
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return AppKey{
+			KID: kid,
+			KTY: "EC",
+			ALG: "ES256",
+			USE: "sig",
+			CRV: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+		}, nil
+
+	case ed25519.PublicKey:
+		return AppKey{
+			KID: kid,
+			KTY: "OKP",
+			ALG: "EdDSA",
+			USE: "sig",
+			CRV: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, nil
+	}
+
+	return AppKey{}, fmt.Errorf("unsupported key type %T", pub)
+}
+
+// encode writes the big-endian bytes of the number in unpadded base64url, as
+// JWK requires for key parameters.
+func encode(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
@@ -0,0 +1,124 @@
+package jwksgrp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+)
+
+func TestToAppKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A coordinate with a leading zero byte shows it is padded to the size
+	// of the curve rather than trimmed.
+	var ecKey *ecdsa.PrivateKey
+	for ecKey == nil || (ecKey.X.BitLen() > 248 && ecKey.Y.BitLen() > 248) {
+		if ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("RSA", func(t *testing.T) {
+		key, err := toAppKey("rsa", publicPEM(t, rsaKey.Public()))
+		if err != nil {
+			t.Fatalf("Should be able to convert the key : %s", err)
+		}
+
+		want := AppKey{
+			KID: "rsa",
+			KTY: "RSA",
+			ALG: "RS256",
+			USE: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		}
+		if key != want {
+			t.Errorf("Should get %+v, got %+v", want, key)
+		}
+	})
+
+	t.Run("EC", func(t *testing.T) {
+		key, err := toAppKey("ec", publicPEM(t, ecKey.Public()))
+		if err != nil {
+			t.Fatalf("Should be able to convert the key : %s", err)
+		}
+
+		want := AppKey{
+			KID: "ec",
+			KTY: "EC",
+			ALG: "ES256",
+			USE: "sig",
+			CRV: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+		}
+		if key != want {
+			t.Errorf("Should get %+v, got %+v", want, key)
+		}
+
+		for _, coord := range []string{key.X, key.Y} {
+			b, err := base64.RawURLEncoding.DecodeString(coord)
+			if err != nil || len(b) != 32 {
+				t.Errorf("Should encode 32 byte coordinates, got %d : %v", len(b), err)
+			}
+		}
+	})
+
+	t.Run("OKP", func(t *testing.T) {
+		key, err := toAppKey("okp", publicPEM(t, edPublic))
+		if err != nil {
+			t.Fatalf("Should be able to convert the key : %s", err)
+		}
+
+		want := AppKey{
+			KID: "okp",
+			KTY: "OKP",
+			ALG: "EdDSA",
+			USE: "sig",
+			CRV: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edPublic),
+		}
+		if key != want {
+			t.Errorf("Should get %+v, got %+v", want, key)
+		}
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := toAppKey("p384", publicPEM(t, p384.Public())); err == nil {
+			t.Error("Should refuse a key on a curve without a signing method")
+		}
+	})
+}
+
+// publicPEM PEM encodes the public key the way the keystore hands it out.
+func publicPEM(t *testing.T, pub crypto.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
@@ -0,0 +1,21 @@
+package jwksgrp
+
+import (
+	"sales-api/business/web/v1/auth"
+	"sales-api/foundation/logger"
+	"sales-api/foundation/web"
+)
+
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	Keys  auth.KeySet
+}
+
+func Route(app *web.App, cfg Config) {
+	hdl := New(cfg.Keys)
+
+	// Clients look for the key set at a well known path, outside of the API
+	// version.
+	app.HandleRootFunc("/.well-known/jwks.json", hdl.Query).Methods("GET")
+}
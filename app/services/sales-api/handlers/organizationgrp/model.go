@@ -0,0 +1,46 @@
+package organizationgrp
+
+import (
+	"fmt"
+	"sales-api/business/core/organization"
+	"sales-api/foundation/validate"
+	"time"
+)
+
+// AppOrganization represents information about an individual organization.
+type AppOrganization struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+func toAppOrganization(org organization.Organization) AppOrganization {
+	return AppOrganization{
+		ID:        org.ID.String(),
+		Name:      org.Name,
+		CreatedAt: org.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: org.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// AppUpdateOrganization contains information needed to update an organization.
+type AppUpdateOrganization struct {
+	Name *string `json:"name" validate:"omitempty,min=1"`
+}
+
+func toCoreUpdateOrganization(app AppUpdateOrganization) organization.UpdateOrganization {
+	return organization.UpdateOrganization{
+		Name: app.Name,
+	}
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppUpdateOrganization) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
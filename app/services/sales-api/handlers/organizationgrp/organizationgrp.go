@@ -0,0 +1,97 @@
+package organizationgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sales-api/business/core/organization"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/business/web/v1/response"
+	"sales-api/foundation/web"
+)
+
+// Handlers manages the set of organization endpoints.
+type Handlers struct {
+	organization *organization.Core
+}
+
+// New constructs a handlers for route access.
+func New(organization *organization.Core) *Handlers {
+	return &Handlers{
+		organization: organization,
+	}
+}
+
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		organization, err := h.organization.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+		h = &Handlers{
+			organization: organization,
+		}
+		return h, nil
+	}
+	return h, nil
+}
+
+// Query returns the organization of the caller.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	org, err := h.queryOrganization(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, organizationResponse(org), http.StatusOK)
+}
+
+// Update updates the organization of the caller.
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppUpdateOrganization
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	org, err := h.queryOrganization(ctx)
+	if err != nil {
+		return err
+	}
+
+	uo := toCoreUpdateOrganization(app)
+
+	org, err = h.organization.Update(ctx, org, uo)
+	if err != nil {
+		return fmt.Errorf("update: organizationID[%s] uo[%+v]: %w", org.ID, uo, err)
+	}
+
+	return web.Respond(ctx, w, organizationResponse(org), http.StatusOK)
+}
+
+// ========================================================================
+
+func (h *Handlers) queryOrganization(ctx context.Context) (organization.Organization, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return organization.Organization{}, err
+	}
+
+	org, err := h.organization.QueryByID(ctx, tenantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, organization.ErrNotFound):
+			return organization.Organization{}, response.NewError(organization.ErrNotFound, http.StatusNotFound)
+		default:
+			return organization.Organization{}, fmt.Errorf("querybyid: id[%s]: %w", tenantID, err)
+		}
+	}
+
+	return org, nil
+}
@@ -0,0 +1,16 @@
+package organizationgrp
+
+import (
+	"sales-api/business/core/organization"
+	"sales-api/business/web/v1/response"
+)
+
+type organizationRes struct {
+	Organization AppOrganization `json:"organization"`
+}
+
+func organizationResponse(org organization.Organization) response.Success[organizationRes] {
+	return response.NewSuccess(organizationRes{
+		Organization: toAppOrganization(org),
+	})
+}
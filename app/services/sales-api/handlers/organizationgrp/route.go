@@ -0,0 +1,39 @@
+package organizationgrp
+
+import (
+	"sales-api/business/core/organization"
+	"sales-api/business/core/organization/stores/organizationdb"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/mid"
+	"sales-api/foundation/logger"
+	"sales-api/foundation/web"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+func Route(app *web.App, cfg Config) {
+
+	orgCore := organization.NewCore(cfg.Log, organizationdb.NewRepository(cfg.Log, cfg.DB))
+
+	authMid := mid.Authenticate(cfg.Auth)
+	ruleAny := mid.Authorize(cfg.Auth, auth.RuleAny)
+	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
+
+	tran := mid.ExecuteInTransaction(cfg.Log, pgx.NewBeginner(cfg.DB))
+
+	hdl := New(orgCore)
+	// PUT===========================================================================
+	app.HandleFunc("/organization", hdl.Update, authMid, ruleAdmin, tran).Methods("PUT")
+
+	// GET===========================================================================
+	app.HandleFunc("/organization", hdl.Query, authMid, ruleAny).Methods("GET")
+
+}
@@ -0,0 +1,123 @@
+package rolegrp
+
+import (
+	"fmt"
+	"sales-api/business/core/role"
+	"sales-api/foundation/validate"
+	"time"
+)
+
+// AppRole represents information about an individual role.
+type AppRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"builtIn"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+func toAppRole(rol role.Role) AppRole {
+	perms := make([]string, len(rol.Permissions))
+	for i, perm := range rol.Permissions {
+		perms[i] = perm.Name()
+	}
+
+	return AppRole{
+		Name:        rol.Name,
+		Description: rol.Description,
+		Permissions: perms,
+		BuiltIn:     rol.BuiltIn,
+		CreatedAt:   rol.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   rol.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toAppRoles(rols []role.Role) []AppRole {
+	items := make([]AppRole, len(rols))
+	for i, rol := range rols {
+		items[i] = toAppRole(rol)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// AppNewRole contains information needed to create a new role.
+type AppNewRole struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+func toCoreNewRole(app AppNewRole) (role.NewRole, error) {
+	perms, err := parsePermissions(app.Permissions)
+	if err != nil {
+		return role.NewRole{}, err
+	}
+
+	nr := role.NewRole{
+		Name:        app.Name,
+		Description: app.Description,
+		Permissions: perms,
+	}
+
+	return nr, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewRole) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// =============================================================================
+
+// AppUpdateRole contains information needed to update a role.
+type AppUpdateRole struct {
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func toCoreUpdateRole(app AppUpdateRole) (role.UpdateRole, error) {
+	var perms []role.Permission
+	if app.Permissions != nil {
+		var err error
+		perms, err = parsePermissions(app.Permissions)
+		if err != nil {
+			return role.UpdateRole{}, err
+		}
+	}
+
+	ur := role.UpdateRole{
+		Description: app.Description,
+		Permissions: perms,
+	}
+
+	return ur, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppUpdateRole) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+
+func parsePermissions(values []string) ([]role.Permission, error) {
+	perms := make([]role.Permission, len(values))
+	for i, value := range values {
+		perm, err := role.ParsePermission(value)
+		if err != nil {
+			return nil, validate.NewFieldsError("permissions", err)
+		}
+		perms[i] = perm
+	}
+	return perms, nil
+}
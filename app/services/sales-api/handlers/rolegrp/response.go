@@ -0,0 +1,16 @@
+package rolegrp
+
+import (
+	"sales-api/business/core/role"
+	"sales-api/business/web/v1/response"
+)
+
+type roleRes struct {
+	Role AppRole `json:"role"`
+}
+
+func roleResponse(rol role.Role) response.Success[roleRes] {
+	return response.NewSuccess(roleRes{
+		Role: toAppRole(rol),
+	})
+}
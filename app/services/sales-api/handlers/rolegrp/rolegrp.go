@@ -0,0 +1,166 @@
+package rolegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sales-api/business/core/role"
+	"sales-api/business/data/transaction"
+	"sales-api/business/web/v1/response"
+	"sales-api/foundation/validate"
+	"sales-api/foundation/web"
+)
+
+// Handlers manages the set of role endpoints.
+type Handlers struct {
+	role *role.Core
+}
+
+// New constructs a handlers for route access.
+func New(role *role.Core) *Handlers {
+	return &Handlers{
+		role: role,
+	}
+}
+
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		role, err := h.role.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+		h = &Handlers{
+			role: role,
+		}
+		return h, nil
+	}
+	return h, nil
+}
+
+// Create adds a new role to the system.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppNewRole
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	nr, err := toCoreNewRole(app)
+	if err != nil {
+		return err
+	}
+
+	rol, err := h.role.Create(ctx, nr)
+	if err != nil {
+		switch {
+		case errors.Is(err, role.ErrUniqueName):
+			return response.NewError(role.ErrUniqueName, http.StatusConflict)
+		case errors.Is(err, role.ErrInvalidName):
+			return validate.NewFieldsError("name", role.ErrInvalidName)
+		}
+		return fmt.Errorf("create: rol[%+v]: %w", nr, err)
+	}
+
+	return web.Respond(ctx, w, roleResponse(rol), http.StatusCreated)
+}
+
+// UpdateByName updates a role by its name.
+func (h *Handlers) UpdateByName(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppUpdateRole
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	rol, err := h.queryRole(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	ur, err := toCoreUpdateRole(app)
+	if err != nil {
+		return err
+	}
+
+	rol, err = h.role.Update(ctx, rol, ur)
+	if err != nil {
+		if errors.Is(err, role.ErrBuiltIn) {
+			return response.NewError(role.ErrBuiltIn, http.StatusConflict)
+		}
+		return fmt.Errorf("update: name[%s] ur[%+v]: %w", rol.Name, ur, err)
+	}
+
+	return web.Respond(ctx, w, roleResponse(rol), http.StatusOK)
+}
+
+// DeleteByName removes a role by its name.
+func (h *Handlers) DeleteByName(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	rol, err := h.queryRole(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.role.Delete(ctx, rol); err != nil {
+		switch {
+		case errors.Is(err, role.ErrBuiltIn):
+			return response.NewError(role.ErrBuiltIn, http.StatusConflict)
+		case errors.Is(err, role.ErrInUse):
+			return response.NewError(role.ErrInUse, http.StatusConflict)
+		}
+		return fmt.Errorf("delete: name[%s]: %w", rol.Name, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryByName returns a role by its name.
+func (h *Handlers) QueryByName(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	rol, err := h.queryRole(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, roleResponse(rol), http.StatusOK)
+}
+
+// Query returns every role.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	rols, err := h.role.QueryAll(ctx)
+	if err != nil {
+		return fmt.Errorf("queryall: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewSuccess(toAppRoles(rols)), http.StatusOK)
+}
+
+// ========================================================================
+
+func (h *Handlers) queryRole(ctx context.Context, r *http.Request) (role.Role, error) {
+	name := web.Param(r, "role")
+
+	rol, err := h.role.QueryByName(ctx, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, role.ErrNotFound):
+			return role.Role{}, response.NewError(role.ErrNotFound, http.StatusNotFound)
+		default:
+			return role.Role{}, fmt.Errorf("querybyname: name[%s]: %w", name, err)
+		}
+	}
+
+	return rol, nil
+}
@@ -0,0 +1,46 @@
+package rolegrp
+
+import (
+	"sales-api/business/core/role"
+	"sales-api/business/core/role/stores/roledb"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/mid"
+	"sales-api/foundation/logger"
+	"sales-api/foundation/web"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build     string
+	Log       *logger.Logger
+	DB        *sqlx.DB
+	Auth      *auth.Auth
+	RoleCache *role.Cache
+}
+
+func Route(app *web.App, cfg Config) {
+
+	rolCore := role.NewCore(cfg.Log, cfg.RoleCache, roledb.NewRepository(cfg.Log, cfg.DB))
+
+	authMid := mid.Authenticate(cfg.Auth)
+	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
+
+	tran := mid.ExecuteInTransaction(cfg.Log, pgx.NewBeginner(cfg.DB))
+
+	hdl := New(rolCore)
+	// POST===========================================================================
+	app.HandleFunc("/roles", hdl.Create, authMid, ruleAdmin, tran).Methods("POST")
+
+	// PUT===========================================================================
+	app.HandleFunc("/roles/{role}", hdl.UpdateByName, authMid, ruleAdmin, tran).Methods("PUT")
+
+	// GET===========================================================================
+	app.HandleFunc("/roles/{role}", hdl.QueryByName, authMid, ruleAdmin).Methods("GET")
+	app.HandleFunc("/roles", hdl.Query, authMid, ruleAdmin).Methods("GET")
+
+	// DELETE===========================================================================
+	app.HandleFunc("/roles/{role}", hdl.DeleteByName, authMid, ruleAdmin, tran).Methods("DELETE")
+
+}
@@ -14,6 +14,7 @@ func parseFilter(r *http.Request) (user.QueryFilter, error) {
 	const (
 		filterByUserID           = "user_id"
 		filterByEmail            = "email"
+		filterByDepartmentID     = "department_id"
 		filterByStartCreatedDate = "start_created_date"
 		filterByEndCreatedDate   = "end_created_date"
 		filterByName             = "name"
@@ -38,6 +39,14 @@ func parseFilter(r *http.Request) (user.QueryFilter, error) {
 		}
 		filter.WithEmail(*addr)
 	}
+
+	if departmentID := values.Get(filterByDepartmentID); departmentID != "" {
+		id, err := uuid.Parse(departmentID)
+		if err != nil {
+			return user.QueryFilter{}, validate.NewFieldsError(filterByDepartmentID, err)
+		}
+		filter.WithDepartmentID(id)
+	}
 	if createdDate := values.Get(filterByStartCreatedDate); createdDate != "" {
 		t, err := time.Parse(time.RFC3339, createdDate)
 		if err != nil {
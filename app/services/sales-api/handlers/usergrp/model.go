@@ -6,6 +6,8 @@ import (
 	"sales-api/business/core/user"
 	"sales-api/foundation/validate"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // AppUser represents information about an individual user.
@@ -15,7 +17,7 @@ type AppUser struct {
 	Email        string   `json:"email"`
 	Roles        []string `json:"roles"`
 	PasswordHash []byte   `json:"-"`
-	Department   string   `json:"department"`
+	DepartmentID string   `json:"departmentId"`
 	Enabled      bool     `json:"enabled"`
 	CreatedAt    string   `json:"createdAt"`
 	UpdatedAt    string   `json:"updatedAt"`
@@ -33,7 +35,7 @@ func toAppUser(usr user.User) AppUser {
 		Email:        usr.Email.Address,
 		Roles:        roles,
 		PasswordHash: usr.PasswordHash,
-		Department:   usr.Department,
+		DepartmentID: departmentID(usr.DepartmentID),
 		Enabled:      usr.Enabled,
 		CreatedAt:    usr.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    usr.UpdatedAt.Format(time.RFC3339),
@@ -45,7 +47,7 @@ type AppNewUser struct {
 	Name            string   `json:"name" validate:"required"`
 	Email           string   `json:"email" validate:"required,email"`
 	Roles           []string `json:"roles" validate:"required"`
-	Department      string   `json:"department"`
+	DepartmentID    string   `json:"departmentId" validate:"omitempty,uuid"`
 	Password        string   `json:"password" validate:"required"`
 	PasswordConfirm string   `json:"passwordConfirm" validate:"eqfield=Password"`
 }
@@ -65,12 +67,20 @@ func toCoreNewUser(app AppNewUser) (user.NewUser, error) {
 		return user.NewUser{}, fmt.Errorf("parsing email: %w", err)
 	}
 
+	var depID uuid.UUID
+	if app.DepartmentID != "" {
+		depID, err = uuid.Parse(app.DepartmentID)
+		if err != nil {
+			return user.NewUser{}, fmt.Errorf("parsing departmentId: %w", err)
+		}
+	}
+
 	usr := user.NewUser{
-		Name:       app.Name,
-		Email:      *addr,
-		Roles:      roles,
-		Department: app.Department,
-		Password:   app.Password,
+		Name:         app.Name,
+		Email:        *addr,
+		Roles:        roles,
+		DepartmentID: depID,
+		Password:     app.Password,
 	}
 
 	return usr, nil
@@ -84,6 +94,15 @@ func (app AppNewUser) Validate() error {
 	return nil
 }
 
+// departmentID returns the string form of a department id, empty when the
+// user isn't in a department.
+func departmentID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
 func toAppUsers(users []user.User) []AppUser {
 	items := make([]AppUser, len(users))
 	for i, usr := range users {
@@ -111,19 +130,46 @@ func (app AppLoginRequest) Validate() error {
 
 // AppLoginResponse contains information returned after user login.
 type AppLoginResponse struct {
-	User  AppUser `json:"user"`
-	Token string  `json:"token"`
+	User         AppUser `json:"user"`
+	Token        string  `json:"token"`
+	RefreshToken string  `json:"refreshToken"`
+}
+
+// AppRefreshRequest contains information needed to refresh an access token.
+type AppRefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppRefreshRequest) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AppLogoutRequest contains information needed to end a session.
+type AppLogoutRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppLogoutRequest) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
 }
 
 // =============================================================================
 // AppUpdateUser contains information needed to update a user.
 type AppUpdateUser struct {
-	Name       *string  `json:"name"`
-	Roles      []string `json:"roles"`
-	Email      *string  `json:"email" validate:"omitempty,email"`
-	Department *string  `json:"department"`
-	Password   *string  `json:"password"`
-	Enabled    *bool    `json:"enabled"`
+	Name         *string  `json:"name"`
+	Roles        []string `json:"roles"`
+	Email        *string  `json:"email" validate:"omitempty,email"`
+	DepartmentID *string  `json:"departmentId"`
+	Password     *string  `json:"password"`
+	Enabled      *bool    `json:"enabled"`
 }
 
 func toCoreUpdateUser(app AppUpdateUser) (user.UpdateUser, error) {
@@ -146,13 +192,27 @@ func toCoreUpdateUser(app AppUpdateUser) (user.UpdateUser, error) {
 			return user.UpdateUser{}, validate.NewFieldsError("email", fmt.Errorf("invalid email: %q", *app.Email))
 		}
 	}
+	// An empty department id removes the user from their department.
+	var depID *uuid.UUID
+	if app.DepartmentID != nil {
+		var id uuid.UUID
+		if *app.DepartmentID != "" {
+			var err error
+			id, err = uuid.Parse(*app.DepartmentID)
+			if err != nil {
+				return user.UpdateUser{}, validate.NewFieldsError("departmentId", fmt.Errorf("invalid department id: %q", *app.DepartmentID))
+			}
+		}
+		depID = &id
+	}
+
 	nu := user.UpdateUser{
-		Name:       app.Name,
-		Email:      addr,
-		Roles:      roles,
-		Department: app.Department,
-		Enabled:    app.Enabled,
-		Password:   app.Password,
+		Name:         app.Name,
+		Email:        addr,
+		Roles:        roles,
+		DepartmentID: depID,
+		Enabled:      app.Enabled,
+		Password:     app.Password,
 	}
 
 	return nu, nil
@@ -1,6 +1,10 @@
 package usergrp
 
 import (
+	"sales-api/business/core/role"
+	"sales-api/business/core/role/stores/roledb"
+	"sales-api/business/core/session"
+	"sales-api/business/core/session/stores/sessiondb"
 	"sales-api/business/core/user"
 	"sales-api/business/core/user/stores/userdb"
 	"sales-api/business/data/dbsql/pgx"
@@ -13,33 +17,43 @@ import (
 )
 
 type Config struct {
-	Build string
-	Log   *logger.Logger
-	DB    *sqlx.DB
-	Auth  *auth.Auth
+	Build         string
+	Log           *logger.Logger
+	DB            *sqlx.DB
+	MaintenanceDB *sqlx.DB
+	Auth          *auth.Auth
+	UserCache     *user.EnabledCache
+	RoleCache     *role.Cache
 }
 
 func Route(app *web.App, cfg Config) {
 
-	usrCore := user.NewCore(cfg.Log, userdb.NewRepository(cfg.Log, cfg.DB))
+	usrCore := user.NewCore(cfg.Log, cfg.UserCache, userdb.NewRepository(cfg.Log, cfg.DB))
+	rolCore := role.NewCore(cfg.Log, cfg.RoleCache, roledb.NewRepository(cfg.Log, cfg.DB))
+	// Logins and refresh tokens are looked up before the tenant is known.
+	loginCore := user.NewCore(cfg.Log, cfg.UserCache, userdb.NewRepository(cfg.Log, cfg.MaintenanceDB))
+	sesCore := session.NewCore(cfg.Log, sessiondb.NewRepository(cfg.Log, cfg.MaintenanceDB))
 
 	authMid := mid.Authenticate(cfg.Auth)
 	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
 	ruleAdminOrSubject := mid.Authorize(cfg.Auth, auth.RuleAdminOrSubject)
+	ruleAdminOrManager := mid.Authorize(cfg.Auth, auth.RuleAdminOrSubjectOrManager)
 
 	tran := mid.ExecuteInTransaction(cfg.Log, pgx.NewBeginner(cfg.DB))
 
-	hdl := New(usrCore, cfg.Auth)
+	hdl := New(usrCore, loginCore, rolCore, sesCore, cfg.Auth)
 	// POST===========================================================================
-	app.HandleFunc("/users", hdl.Create).Methods("POST")
+	app.HandleFunc("/users", hdl.Create, authMid, ruleAdmin).Methods("POST")
 	app.HandleFunc("/users/login", hdl.Login).Methods("POST")
+	app.HandleFunc("/users/token/refresh", hdl.Refresh).Methods("POST")
+	app.HandleFunc("/users/logout", hdl.Logout, authMid).Methods("POST")
 
 	// PUT===========================================================================
 	app.HandleFunc("/users/{user_id}", hdl.UpdateByID, authMid, ruleAdminOrSubject, tran).Methods("PUT")
 
 	// GET===========================================================================
 
-	app.HandleFunc("/users/{user_id}", hdl.QueryByID, authMid, ruleAdminOrSubject).Methods("GET")
+	app.HandleFunc("/users/{user_id}", hdl.QueryByID, authMid, ruleAdminOrManager).Methods("GET")
 	app.HandleFunc("/users", hdl.Query, authMid, ruleAdmin).Methods("GET")
 
 	// DELETE===========================================================================
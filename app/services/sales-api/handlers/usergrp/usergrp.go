@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/mail"
+	"sales-api/business/core/role"
+	"sales-api/business/core/session"
 	"sales-api/business/core/user"
 	"sales-api/business/data/page"
+	"sales-api/business/data/tenant"
 	"sales-api/business/data/transaction"
 	"sales-api/business/web/v1/auth"
 	"sales-api/business/web/v1/response"
@@ -19,17 +22,25 @@ import (
 	"github.com/google/uuid"
 )
 
-// Handlers manages the set of user endpoints.
+// Handlers manages the set of user endpoints. Logging in has to find the
+// user before the tenant is known, login runs where row level security
+// doesn't apply.
 type Handlers struct {
-	user *user.Core
-	auth *auth.Auth
+	user    *user.Core
+	login   *user.Core
+	role    *role.Core
+	session *session.Core
+	auth    *auth.Auth
 }
 
 // New constructs a handlers for route access.
-func New(user *user.Core, auth *auth.Auth) *Handlers {
+func New(user *user.Core, login *user.Core, role *role.Core, session *session.Core, auth *auth.Auth) *Handlers {
 	return &Handlers{
-		user: user,
-		auth: auth,
+		user:    user,
+		login:   login,
+		role:    role,
+		session: session,
+		auth:    auth,
 	}
 }
 
@@ -40,8 +51,11 @@ func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, erro
 			return nil, err
 		}
 		h = &Handlers{
-			user: user,
-			auth: h.auth,
+			user:    user,
+			login:   h.login,
+			role:    h.role,
+			session: h.session,
+			auth:    h.auth,
 		}
 		return h, nil
 	}
@@ -59,23 +73,27 @@ func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return response.NewError(err, http.StatusBadRequest)
 	}
 
+	if err := h.checkRoles(ctx, nc.Roles); err != nil {
+		return err
+	}
+
 	usr, err := h.user.Create(ctx, nc)
 	if err != nil {
 		if errors.Is(err, user.ErrUniqueEmail) {
 			return response.NewError(user.ErrUniqueEmail, http.StatusConflict)
 		}
+		if errors.Is(err, user.ErrDepartmentNotFound) {
+			return validate.NewFieldsError("departmentId", user.ErrDepartmentNotFound)
+		}
 		return fmt.Errorf("create: usr[%+v]: %w", usr, err)
 	}
 
 	return web.Respond(ctx, w, userResponse(usr), http.StatusCreated)
 }
 
-// Create adds a new user to the system.
+// Login authenticates a user and returns an access token along with the
+// refresh token that starts a new session.
 func (h *Handlers) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	kid := r.Header.Get("kid")
-	if kid == "" {
-		return validate.NewFieldsError("kid", errors.New("missing kid"))
-	}
 	var app AppLoginRequest
 	if err := web.Decode(r, &app); err != nil {
 		return response.NewError(err, http.StatusBadRequest)
@@ -84,38 +102,115 @@ func (h *Handlers) Login(ctx context.Context, w http.ResponseWriter, r *http.Req
 	if err != nil {
 		return validate.NewFieldsError("email", errors.New("invalid email"))
 	}
-	usr, err := h.user.Authenticate(ctx, *email, app.Password)
+	usr, err := h.login.Authenticate(ctx, *email, app.Password)
 	if err != nil {
 		switch {
 		case errors.Is(err, user.ErrAuthenticationFailure):
 			return auth.NewAuthError(err.Error())
+		case errors.Is(err, user.ErrDisabled):
+			return auth.NewAuthError(user.ErrDisabled.Error())
 		case errors.Is(err, user.ErrNotFound):
 			return response.NewError(err, http.StatusNotFound)
 		default:
 			return fmt.Errorf("login: usr[%+v]: %w", usr, err)
 		}
 	}
-	claims := auth.Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   usr.ID.String(),
-			Issuer:    "service project",
-			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
-		},
-		Roles: usr.Roles,
+
+	refreshToken, rt, err := h.session.Start(ctx, usr.TenantID, usr.ID)
+	if err != nil {
+		return fmt.Errorf("start session: userID[%s]: %w", usr.ID, err)
 	}
-	token, err := h.auth.GenerateToken(kid, claims)
 
+	token, err := h.generateToken(usr, rt.SessionID)
 	if err != nil {
-		return fmt.Errorf("generatetoken: %w", err)
+		return err
 	}
 
 	return web.Respond(ctx, w, response.NewSuccess(AppLoginResponse{
-		User:  toAppUser(usr),
-		Token: token,
+		User:         toAppUser(usr),
+		Token:        token,
+		RefreshToken: refreshToken,
 	}), http.StatusCreated)
 }
 
+// Refresh exchanges a refresh token for a new access token and the next
+// refresh token of the session. A refresh token that is presented twice
+// ends the session.
+func (h *Handlers) Refresh(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app AppRefreshRequest
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	refreshToken, rt, err := h.session.Rotate(ctx, app.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrNotFound),
+			errors.Is(err, session.ErrExpired),
+			errors.Is(err, session.ErrReused):
+			return auth.NewAuthError(err.Error())
+		default:
+			return fmt.Errorf("rotate: %w", err)
+		}
+	}
+
+	// The roles may have changed since the last token, read the user again
+	// as the organization the session belongs to.
+	ctx = tenant.Set(ctx, rt.TenantID)
+	usr, err := h.user.QueryByID(ctx, rt.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return auth.NewAuthError(err.Error())
+		}
+		return fmt.Errorf("querybyid: userID[%s]: %w", rt.UserID, err)
+	}
+	if !usr.Enabled {
+		return auth.NewAuthError("user disabled")
+	}
+
+	token, err := h.generateToken(usr, rt.SessionID)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, response.NewSuccess(AppLoginResponse{
+		User:         toAppUser(usr),
+		Token:        token,
+		RefreshToken: refreshToken,
+	}), http.StatusCreated)
+}
+
+// Logout ends the session the refresh token belongs to, which revokes every
+// access token issued in it. The caller's access token is revoked by its id
+// as well, for tokens that weren't issued in a session.
+func (h *Handlers) Logout(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app AppLogoutRequest
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	claims := auth.GetClaims(ctx)
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject %q", claims.Subject)
+	}
+
+	if err := h.session.End(ctx, app.RefreshToken, userID); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return response.NewError(session.ErrNotFound, http.StatusNotFound)
+		}
+		return fmt.Errorf("end session: userID[%s]: %w", userID, err)
+	}
+
+	if claims.ExpiresAt != nil {
+		if err := h.session.RevokeAccess(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return fmt.Errorf("revoke access: userID[%s]: %w", userID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
 // QueryByID returns a user by its ID.
 func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	userID := auth.GetUserID(ctx)
@@ -149,8 +244,23 @@ func (h *Handlers) UpdateByID(ctx context.Context, w http.ResponseWriter, r *htt
 	if err != nil {
 		return err
 	}
+
+	// Users may edit themselves, but what they are allowed to do and whether
+	// they can log in is up to an admin.
+	if uu.Roles != nil || uu.Enabled != nil {
+		if err := h.auth.Authorize(ctx, auth.GetClaims(ctx), userID, auth.RuleAdminOnly); err != nil {
+			return auth.NewAuthError("update: only admins can change roles or enabled: %s", err)
+		}
+	}
+
+	if err := h.checkRoles(ctx, uu.Roles); err != nil {
+		return err
+	}
 	nu, err := h.user.Update(ctx, usr, uu)
 	if err != nil {
+		if errors.Is(err, user.ErrDepartmentNotFound) {
+			return validate.NewFieldsError("departmentId", user.ErrDepartmentNotFound)
+		}
 		return fmt.Errorf("update: userID[%s] uu[%+v]: %w", userID, uu, err)
 	}
 
@@ -165,7 +275,7 @@ func (h *Handlers) DeleteByID(ctx context.Context, w http.ResponseWriter, r *htt
 	if err != nil {
 		switch {
 		case errors.Is(err, user.ErrNotFound):
-			response.NewError(user.ErrNotFound, http.StatusNotFound)
+			return response.NewError(user.ErrNotFound, http.StatusNotFound)
 		default:
 			return err
 		}
@@ -218,3 +328,44 @@ func (h *Handlers) queryUserByID(ctx context.Context, userID uuid.UUID) (user.Us
 	}
 	return usr, nil
 }
+
+// generateToken signs a one hour access token for the user. Every token
+// carries its own id so it can be revoked. Auth fills in the issuer and
+// audience.
+func (h *Handlers) generateToken(usr user.User, sessionID uuid.UUID) (string, error) {
+	now := time.Now().UTC()
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   usr.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		TenantID:  usr.TenantID,
+		SessionID: sessionID,
+		Roles:     usr.Roles,
+	}
+
+	token, err := h.auth.GenerateToken(claims)
+	if err != nil {
+		return "", fmt.Errorf("generatetoken: %w", err)
+	}
+
+	return token, nil
+}
+
+// checkRoles makes sure every role is one of the built-in roles or belongs
+// to the caller's organization. Role names are known across organizations
+// but only grant something inside their own.
+func (h *Handlers) checkRoles(ctx context.Context, roles []user.Role) error {
+	for _, rl := range roles {
+		if _, err := h.role.QueryByName(ctx, rl.Name()); err != nil {
+			if errors.Is(err, role.ErrNotFound) {
+				return validate.NewFieldsError("roles", fmt.Errorf("invalid value for role %q", rl.Name()))
+			}
+			return fmt.Errorf("querybyname: name[%s]: %w", rl.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sales-api/app/services/sales-api/handlers/decisiongrp"
+	"sales-api/business/web/v1/auth"
+	"sales-api/business/web/v1/response"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DecisionTestSuite struct {
+	suite.Suite
+	web *WebTest
+}
+
+func (s *DecisionTestSuite) SetupSuite() {
+	s.web = NewWebTest(s.T())
+}
+func (s *DecisionTestSuite) TearDownSuite() {
+	s.web.TearDown()
+}
+
+// ==================================================
+
+func (suite *DecisionTestSuite) TestQueryDenied() {
+
+	// A user asking for the list of users is denied, which is recorded.
+	r := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	w := httptest.NewRecorder()
+	r.Header.Set("Authorization", "Bearer "+suite.web.userToken)
+
+	suite.web.app.ServeHTTP(w, r)
+	suite.NotEqual(http.StatusOK, w.Code)
+
+	url := "/v1/decisions?allowed=false&rule=" + auth.RuleAdminOnly
+	r = httptest.NewRequest(http.MethodGet, url, nil)
+	w = httptest.NewRecorder()
+	r.Header.Set("Authorization", "Bearer "+suite.web.adminToken)
+
+	suite.web.app.ServeHTTP(w, r)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var resp response.PageDocument[decisiongrp.AppDecision]
+	suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	suite.Equal(1, resp.Total)
+	suite.Len(resp.Items, 1)
+
+	d := resp.Items[0]
+	suite.False(d.Allowed)
+	suite.NotEmpty(d.Reason)
+	suite.Equal("GET /v1/users", d.Route)
+	suite.Equal(auth.PolicyEmbedded, d.PolicyVersion)
+}
+
+// ================================================
+func TestDecision(t *testing.T) {
+	suite.Run(t, new(DecisionTestSuite))
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sales-api/app/services/sales-api/handlers/jwksgrp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JWKSTestSuite struct {
+	suite.Suite
+	web *WebTest
+}
+
+func (s *JWKSTestSuite) SetupSuite() {
+	s.web = NewWebTest(s.T())
+}
+func (s *JWKSTestSuite) TearDownSuite() {
+	s.web.TearDown()
+}
+
+// ==================================================
+
+func (suite *JWKSTestSuite) TestQuery() {
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	suite.web.app.ServeHTTP(w, r)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Header().Get("Cache-Control"), "max-age=")
+
+	var resp jwksgrp.AppKeySet
+	suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	suite.Len(resp.Keys, 1)
+	suite.Equal("RS256", resp.Keys[0].ALG)
+	suite.Equal("sig", resp.Keys[0].USE)
+	suite.NotEmpty(resp.Keys[0].KID)
+}
+
+// ================================================
+func TestJWKS(t *testing.T) {
+	suite.Run(t, new(JWKSTestSuite))
+}
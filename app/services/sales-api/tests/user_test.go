@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"sales-api/app/services/sales-api/handlers/usergrp"
 	"sales-api/business/web/v1/response"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -48,6 +49,28 @@ func (suite *UserTestSuite) TestQueryUsers() {
 
 }
 
+func (suite *UserTestSuite) TestUpdateSelf() {
+	const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+	update := func(token string, body string) int {
+		r := httptest.NewRequest(http.MethodPut, "/v1/users/"+userID, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.Header.Set("Authorization", "Bearer "+token)
+
+		suite.web.app.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// A user can change their own details, but not their roles or whether
+	// they are enabled.
+	suite.Equal(http.StatusOK, update(suite.web.userToken, `{"name":"User Gopher"}`))
+	suite.Equal(http.StatusUnauthorized, update(suite.web.userToken, `{"roles":["ADMIN"]}`))
+	suite.Equal(http.StatusUnauthorized, update(suite.web.userToken, `{"enabled":true}`))
+
+	// An admin can.
+	suite.Equal(http.StatusOK, update(suite.web.adminToken, `{"roles":["USER"]}`))
+}
+
 // ================================================
 func TestUser(t *testing.T) {
 	suite.Run(t, new(UserTestSuite))
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"runtime/debug"
@@ -40,10 +41,15 @@ func NewWebTest(t *testing.T) *WebTest {
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
 	handler := v1.APIMux(v1.APIMuxConfig{
-		Shutdown: shutdown,
-		Log:      test.Log,
-		DB:       test.DB,
-		Auth:     test.Auth,
+		Shutdown:      shutdown,
+		Log:           test.Log,
+		DB:            test.DB,
+		MaintenanceDB: test.MaintenanceDB,
+		Auth:          test.Auth,
+		Keys:          test.Keys,
+		UserCache:     test.Caches.User,
+		RoleCache:     test.Caches.Role,
+		PublicURL:     &url.URL{Scheme: "http", Host: "localhost:3000"},
 	}, handlers.Routes())
 
 	usrToken, err := test.TokenV1("user@example.com", "gophers")
@@ -22,6 +22,11 @@ func Migrate() error {
 			MaxIdleConns int    `conf:"default:2"`
 			MaxOpenConns int    `conf:"default:0"`
 			DisableTLS   bool   `conf:"default:true"`
+
+			// The roles the service connects as are created by the
+			// migrations, these are the passwords they get.
+			AppPassword         string `conf:"default:postgres,mask"`
+			MaintenancePassword string `conf:"default:postgres,mask"`
 		}
 	}
 
@@ -59,5 +64,13 @@ func Migrate() error {
 		return fmt.Errorf("migrate database: %w", err)
 	}
 	fmt.Println("migrations complete")
+
+	if err := dbmigrate.SetPassword(ctx, db, dbmigrate.AppRole, cfg.DB.AppPassword); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+	if err := dbmigrate.SetPassword(ctx, db, dbmigrate.MaintenanceRole, cfg.DB.MaintenancePassword); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+	fmt.Println("role passwords set")
 	return nil
 }
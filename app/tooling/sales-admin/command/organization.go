@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"sales-api/business/core/organization"
+	"sales-api/business/core/organization/stores/organizationdb"
+	"sales-api/business/core/user"
+	"sales-api/business/core/user/stores/userdb"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/tenant"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/ardanlabs/conf/v3"
+)
+
+// Organization creates a new organization and the admin user that manages
+// it. It runs as the maintenance role, as the organization is not yet a
+// tenant anyone can act for.
+//
+//	sales-admin organization <name> <admin name> <admin email> <admin password>
+func Organization() error {
+
+	var cfg struct {
+		conf.Args
+		DB struct {
+			User         string `conf:"default:sales_maintenance"`
+			Password     string `conf:"default:postgres,mask"`
+			Host         string `conf:"default:database-service.sales-system.svc.cluster.local"`
+			Name         string `conf:"default:postgres"`
+			MaxIdleConns int    `conf:"default:2"`
+			MaxOpenConns int    `conf:"default:0"`
+			DisableTLS   bool   `conf:"default:true"`
+		}
+	}
+
+	const prefix = "SALES"
+	help, err := conf.Parse(prefix, &cfg)
+	if err != nil {
+		if errors.Is(err, conf.ErrHelpWanted) {
+			fmt.Println(help)
+			return nil
+		}
+		return fmt.Errorf("parsing config: %w", err)
+	}
+
+	orgName := cfg.Args.Num(1)
+	adminName := cfg.Args.Num(2)
+	password := cfg.Args.Num(4)
+	if orgName == "" || adminName == "" || password == "" {
+		return errors.New("usage: organization <name> <admin name> <admin email> <admin password>")
+	}
+
+	email, err := mail.ParseAddress(cfg.Args.Num(3))
+	if err != nil {
+		return fmt.Errorf("parsing admin email: %w", err)
+	}
+
+	db, err := pgx.Open(pgx.Config{
+		User:         cfg.DB.User,
+		Password:     cfg.DB.Password,
+		Host:         cfg.DB.Host,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+	})
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The stores log every query, the admin's password hash included.
+	log := logger.New(os.Stdout, logger.LevelError, "SALES-ADMIN", func(context.Context) string { return "" })
+
+	tx, err := pgx.NewBeginner(db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	orgCore, err := organization.NewCore(log, organizationdb.NewRepository(log, db)).ExecuteUnderTransaction(tx)
+	if err != nil {
+		return err
+	}
+	usrCore, err := user.NewCore(log, user.NewEnabledCache(), userdb.NewRepository(log, db)).ExecuteUnderTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	org, err := orgCore.Create(ctx, organization.NewOrganization{Name: orgName})
+	if err != nil {
+		return fmt.Errorf("create organization: %w", err)
+	}
+
+	usr, err := usrCore.Create(tenant.Set(ctx, org.ID), user.NewUser{
+		Name:     adminName,
+		Email:    *email,
+		Roles:    []user.Role{user.RoleAdmin},
+		Password: password,
+	})
+	if err != nil {
+		return fmt.Errorf("create admin: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	fmt.Println("organization created:", org.ID)
+	fmt.Println("admin created:", usr.ID)
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"sales-api/app/tooling/sales-admin/command"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,13 +19,35 @@ import (
 
 func main() {
 
-	err := command.Migrate()
+	// Without a command the database is migrated, which is what the init
+	// container runs.
+	var err error
+	switch commandName() {
+	case "organization":
+		err = command.Organization()
+	case "", "migrate":
+		err = command.Migrate()
+	default:
+		err = fmt.Errorf("unknown command %q, expected migrate or organization", commandName())
+	}
+
 	if err != nil {
 		log.Fatal(err)
 	}
 
 }
 
+// commandName returns the first argument that isn't a flag. Flags take
+// their value as --name=value.
+func commandName() string {
+	for _, arg := range os.Args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
+
 func genKey() (*rsa.PrivateKey, error) {
 	// Generate a new private key.
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
@@ -0,0 +1,287 @@
+// Package appointment provides the core business API for the meetings sales
+// reps schedule with customers.
+package appointment
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sales-api/business/data/order"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound           = errors.New("appointment not found")
+	ErrConflict           = errors.New("appointment conflicts with another appointment")
+	ErrInvalidTimeRange   = errors.New("appointment must end after it starts")
+	ErrInvalidTimeZone    = errors.New("unknown time zone")
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrOwnerNotFound      = errors.New("owner not found")
+	ErrFeedNotFound       = errors.New("calendar feed not found")
+	ErrFeedTokenMalformed = errors.New("calendar feed token is malformed")
+)
+
+// conflictHorizon is how far ahead conflicts are checked for appointments
+// that repeat forever.
+const conflictHorizon = 365 * 24 * time.Hour
+
+// Repository interface declares the behavior this package needs to perists and
+// retrieve data.
+type Repository interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Repository, error)
+	Create(ctx context.Context, appt Appointment) error
+	Update(ctx context.Context, appt Appointment) error
+	Delete(ctx context.Context, appointmentID uuid.UUID) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page int, pageSize int) ([]Appointment, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, appointmentID uuid.UUID) (Appointment, error)
+	QueryByOwnerBetween(ctx context.Context, ownerID uuid.UUID, from time.Time, to time.Time) ([]Appointment, error)
+	LockOwner(ctx context.Context, ownerID uuid.UUID) error
+	UpsertFeed(ctx context.Context, feed Feed) error
+	QueryFeedByTokenHash(ctx context.Context, tokenHash string) (Feed, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for appointment access.
+type Core struct {
+	repository Repository
+	log        *logger.Logger
+}
+
+// NewCore constructs a core for appointment api access.
+func NewCore(log *logger.Logger, repository Repository) *Core {
+	return &Core{
+		repository: repository,
+		log:        log,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	trs, err := c.repository.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	c = &Core{
+		repository: trs,
+		log:        c.log,
+	}
+
+	return c, nil
+}
+
+// Create adds a new appointment to the owner's calendar. ErrConflict is
+// returned if any occurrence overlaps one of the owner's other appointments.
+func (c *Core) Create(ctx context.Context, na NewAppointment) (Appointment, error) {
+	now := time.Now()
+
+	appt := Appointment{
+		ID:          uuid.New(),
+		OwnerID:     na.OwnerID,
+		Title:       na.Title,
+		Description: na.Description,
+		Location:    na.Location,
+		Attendees:   na.Attendees,
+		TimeZone:    na.TimeZone,
+		StartsAt:    na.StartsAt,
+		EndsAt:      na.EndsAt,
+		Recurrence:  na.Recurrence,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := c.validate(ctx, appt); err != nil {
+		return Appointment{}, err
+	}
+
+	if err := c.repository.Create(ctx, appt); err != nil {
+		return Appointment{}, fmt.Errorf("create: %w", err)
+	}
+
+	return appt, nil
+}
+
+// Update modifies information about an appointment.
+func (c *Core) Update(ctx context.Context, appt Appointment, ua UpdateAppointment) (Appointment, error) {
+	if ua.Title != nil {
+		appt.Title = *ua.Title
+	}
+	if ua.Description != nil {
+		appt.Description = *ua.Description
+	}
+	if ua.Location != nil {
+		appt.Location = *ua.Location
+	}
+	if ua.Attendees != nil {
+		appt.Attendees = ua.Attendees
+	}
+	if ua.TimeZone != nil {
+		appt.TimeZone = *ua.TimeZone
+	}
+	if ua.StartsAt != nil {
+		appt.StartsAt = *ua.StartsAt
+	}
+	if ua.EndsAt != nil {
+		appt.EndsAt = *ua.EndsAt
+	}
+	if ua.Recurrence != nil {
+		appt.Recurrence = *ua.Recurrence
+	}
+
+	appt.UpdatedAt = time.Now()
+
+	if err := c.validate(ctx, appt); err != nil {
+		return Appointment{}, err
+	}
+
+	if err := c.repository.Update(ctx, appt); err != nil {
+		return Appointment{}, fmt.Errorf("update: %w", err)
+	}
+
+	return appt, nil
+}
+
+// Delete removes the specified appointment.
+func (c *Core) Delete(ctx context.Context, appointmentID uuid.UUID) error {
+	if err := c.repository.Delete(ctx, appointmentID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// QueryByID returns the appointment by its ID,
+// returns "ErrNotFound" if the appointment record is not found
+func (c *Core) QueryByID(ctx context.Context, appointmentID uuid.UUID) (Appointment, error) {
+	appt, err := c.repository.QueryByID(ctx, appointmentID)
+	if err != nil {
+		return Appointment{}, fmt.Errorf("query: appointment_id[%s]: %w", appointmentID, err)
+	}
+	return appt, nil
+}
+
+// Query retrieves a list of existing appointments.
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page int, pageSize int) ([]Appointment, error) {
+	appts, err := c.repository.Query(ctx, filter, orderBy, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return appts, nil
+}
+
+// Count returns the total number of appointments.
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.repository.Count(ctx, filter)
+}
+
+// ============================================================================
+
+// RotateFeedToken issues a new calendar feed token for the owner, replacing
+// any previous one. The token is only returned here; just its hash is kept.
+func (c *Core) RotateFeedToken(ctx context.Context, ownerID uuid.UUID) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	feed := Feed{
+		OwnerID:   ownerID,
+		TokenHash: hashFeedToken(token),
+		CreatedAt: time.Now(),
+	}
+
+	if err := c.repository.UpsertFeed(ctx, feed); err != nil {
+		return "", fmt.Errorf("upsertfeed: %w", err)
+	}
+
+	return token, nil
+}
+
+// QueryFeedByToken returns the calendar feed a token belongs to, which names
+// the user and their organization. Returns "ErrFeedNotFound" if the token is
+// unknown.
+func (c *Core) QueryFeedByToken(ctx context.Context, token string) (Feed, error) {
+	if _, err := base64.RawURLEncoding.DecodeString(token); err != nil {
+		return Feed{}, ErrFeedTokenMalformed
+	}
+
+	feed, err := c.repository.QueryFeedByTokenHash(ctx, hashFeedToken(token))
+	if err != nil {
+		return Feed{}, fmt.Errorf("queryfeedbytokenhash: %w", err)
+	}
+	return feed, nil
+}
+
+// ============================================================================
+
+// validate applies the business rules to an appointment before it is stored.
+func (c *Core) validate(ctx context.Context, appt Appointment) error {
+	if !appt.EndsAt.After(appt.StartsAt) {
+		return ErrInvalidTimeRange
+	}
+
+	loc, err := time.LoadLocation(appt.TimeZone)
+	if err != nil {
+		return fmt.Errorf("time_zone[%s]: %w", appt.TimeZone, ErrInvalidTimeZone)
+	}
+
+	if err := appt.Recurrence.validate(appt.StartsAt.In(loc)); err != nil {
+		return fmt.Errorf("%s: %w", err, ErrInvalidRecurrence)
+	}
+
+	if err := c.checkConflicts(ctx, appt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkConflicts looks for another appointment of the same owner with an
+// occurrence that overlaps one of appt's occurrences. The owner is locked
+// first so a concurrent write can't pass the same check; the core has to run
+// under a transaction for the lock to last until appt is stored.
+func (c *Core) checkConflicts(ctx context.Context, appt Appointment) error {
+	if err := c.repository.LockOwner(ctx, appt.OwnerID); err != nil {
+		return fmt.Errorf("lockowner: %w", err)
+	}
+
+	from := appt.StartsAt
+	to, ok := appt.SeriesEndsAt()
+	if !ok {
+		to = from.Add(conflictHorizon)
+	}
+
+	others, err := c.repository.QueryByOwnerBetween(ctx, appt.OwnerID, from, to)
+	if err != nil {
+		return fmt.Errorf("querybyownerbetween: %w", err)
+	}
+
+	occurrences := appt.Occurrences(from, to)
+	for _, other := range others {
+		if other.ID == appt.ID {
+			continue
+		}
+		if overlaps(occurrences, appt.Duration(), other.Occurrences(from, to), other.Duration()) {
+			return fmt.Errorf("appointment_id[%s]: %w", other.ID, ErrConflict)
+		}
+	}
+
+	return nil
+}
+
+// hashFeedToken returns the form of a feed token that is stored.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
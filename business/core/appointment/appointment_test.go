@@ -0,0 +1,180 @@
+package appointment_test
+
+import (
+	"context"
+	"net/mail"
+	"sales-api/business/core/appointment"
+	"sales-api/business/core/appointment/stores/appointmentdb"
+	"sales-api/business/core/user"
+	"sales-api/business/data/actor"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AppointmentTestSuite struct {
+	suite.Suite
+	test *test.Test
+	usr  user.User
+	ctx  context.Context
+}
+
+func (s *AppointmentTestSuite) SetupSuite() {
+	s.test = test.New(s.T())
+
+	email, err := mail.ParseAddress("rep@gmail.com")
+	s.NoError(err)
+	s.usr, err = s.test.CoreAPIs.User.Create(s.test.Context(), user.NewUser{
+		Name:     "Rep",
+		Email:    *email,
+		Roles:    []user.Role{user.RoleUser},
+		Password: "password",
+	})
+	s.NoError(err)
+
+	// Appointments are only visible to the user acting on them.
+	s.ctx = actor.Set(s.test.Context(), s.usr.ID)
+}
+func (s *AppointmentTestSuite) TearDownSuite() {
+	s.test.TearDown()
+}
+
+// ==================================================
+
+func (suite *AppointmentTestSuite) TestCreateConflict() {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	suite.NoError(err)
+
+	weekly, err := appointment.ParseRecurrence("FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10")
+	suite.NoError(err)
+
+	// Wednesday 21st October 2026, 09:00 - 10:00 in Berlin.
+	startsAt := time.Date(2026, 10, 21, 9, 0, 0, 0, loc)
+	na := appointment.NewAppointment{
+		OwnerID:    suite.usr.ID,
+		Title:      "Weekly sync",
+		TimeZone:   "Europe/Berlin",
+		StartsAt:   startsAt,
+		EndsAt:     startsAt.Add(time.Hour),
+		Recurrence: weekly,
+	}
+	appt := suite.createAppointment(na)
+
+	// The following Monday is a recurrence, after the daylight saving change.
+	conflict := appointment.NewAppointment{
+		OwnerID:  suite.usr.ID,
+		Title:    "Customer call",
+		TimeZone: "UTC",
+		StartsAt: time.Date(2026, 10, 26, 8, 30, 0, 0, time.UTC),
+		EndsAt:   time.Date(2026, 10, 26, 8, 45, 0, 0, time.UTC),
+	}
+	_, err = suite.test.CoreAPIs.Appointment.Create(suite.ctx, conflict)
+	suite.ErrorIs(err, appointment.ErrConflict)
+
+	// Tuesdays are free.
+	conflict.StartsAt = conflict.StartsAt.AddDate(0, 0, 1)
+	conflict.EndsAt = conflict.EndsAt.AddDate(0, 0, 1)
+	suite.createAppointment(conflict)
+
+	// Updating an appointment doesn't conflict with itself.
+	title := "Weekly team sync"
+	_, err = suite.test.CoreAPIs.Appointment.Update(suite.ctx, appt, appointment.UpdateAppointment{Title: &title})
+	suite.NoError(err)
+}
+
+func (suite *AppointmentTestSuite) TestFeedToken() {
+	token, err := suite.test.CoreAPIs.Appointment.RotateFeedToken(suite.ctx, suite.usr.ID)
+	suite.NoError(err)
+
+	// The token is looked up before the tenant is known, as the service
+	// does that runs without row level security.
+	feedCore := appointment.NewCore(suite.test.Log, appointmentdb.NewRepository(suite.test.Log, suite.test.MaintenanceDB))
+
+	feed, err := feedCore.QueryFeedByToken(context.Background(), token)
+	suite.NoError(err)
+	suite.Equal(suite.usr.ID, feed.OwnerID)
+	suite.Equal(test.DefaultTenantID, feed.TenantID)
+
+	// Rotating revokes the previous token.
+	_, err = suite.test.CoreAPIs.Appointment.RotateFeedToken(suite.ctx, suite.usr.ID)
+	suite.NoError(err)
+
+	_, err = feedCore.QueryFeedByToken(context.Background(), token)
+	suite.ErrorIs(err, appointment.ErrFeedNotFound)
+}
+
+// TestOwnerVisibility runs a statement that doesn't filter by owner, the
+// database does it instead.
+func (suite *AppointmentTestSuite) TestOwnerVisibility() {
+	email, err := mail.ParseAddress("other-rep@gmail.com")
+	suite.NoError(err)
+	other, err := suite.test.CoreAPIs.User.Create(suite.test.Context(), user.NewUser{
+		Name:     "Other Rep",
+		Email:    *email,
+		Roles:    []user.Role{user.RoleUser},
+		Password: "password",
+	})
+	suite.NoError(err)
+
+	startsAt := time.Date(2026, 11, 7, 9, 0, 0, 0, time.UTC)
+	na := appointment.NewAppointment{
+		Title:    "Pipeline review",
+		TimeZone: "UTC",
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(time.Hour),
+	}
+
+	na.OwnerID = suite.usr.ID
+	suite.createAppointment(na)
+
+	otherCtx := actor.Set(suite.test.Context(), other.ID)
+	na.OwnerID = other.ID
+	_, err = suite.test.CoreAPIs.Appointment.Create(otherCtx, na)
+	suite.NoError(err)
+
+	// Nobody can create appointments for a user they don't act on.
+	na.OwnerID = suite.usr.ID
+	_, err = suite.test.CoreAPIs.Appointment.Create(otherCtx, na)
+	suite.Error(err)
+
+	type row struct {
+		OwnerID uuid.UUID `db:"owner_id"`
+	}
+	const q = `SELECT owner_id FROM appointments`
+
+	owners := func(ctx context.Context) map[uuid.UUID]bool {
+		var rows []row
+		err := pgx.NamedQuerySlice(ctx, suite.test.Log, suite.test.DB, q, struct{}{}, &rows)
+		suite.NoError(err)
+
+		m := make(map[uuid.UUID]bool)
+		for _, r := range rows {
+			m[r.OwnerID] = true
+		}
+		return m
+	}
+
+	suite.Equal(map[uuid.UUID]bool{suite.usr.ID: true}, owners(suite.ctx))
+	suite.Equal(map[uuid.UUID]bool{other.ID: true}, owners(otherCtx))
+
+	// Once authorized to act on the other user, their appointments show too.
+	targetCtx := actor.SetTarget(suite.ctx, other.ID)
+	suite.Equal(map[uuid.UUID]bool{suite.usr.ID: true, other.ID: true}, owners(targetCtx))
+}
+
+func (suite *AppointmentTestSuite) createAppointment(na appointment.NewAppointment) appointment.Appointment {
+	appt, err := suite.test.CoreAPIs.Appointment.Create(suite.ctx, na)
+	suite.NoError(err)
+	suite.NotEmpty(appt)
+	suite.Equal(na.Title, appt.Title)
+	return appt
+}
+
+// ================================================
+func TestAppointment(t *testing.T) {
+	suite.Run(t, new(AppointmentTestSuite))
+}
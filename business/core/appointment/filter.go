@@ -0,0 +1,55 @@
+package appointment
+
+import (
+	"fmt"
+	"sales-api/foundation/validate"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on. The
+// start and end dates select every appointment with an occurrence inside
+// that window.
+type QueryFilter struct {
+	ID        *uuid.UUID `validate:"omitempty"`
+	OwnerID   *uuid.UUID `validate:"omitempty"`
+	Title     *string    `validate:"omitempty,min=3"`
+	StartDate *time.Time `validate:"omitempty"`
+	EndDate   *time.Time `validate:"omitempty"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (qf *QueryFilter) Validate() error {
+	if err := validate.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// WithAppointmentID sets the ID field of the QueryFilter value.
+func (qf *QueryFilter) WithAppointmentID(appointmentID uuid.UUID) {
+	qf.ID = &appointmentID
+}
+
+// WithOwnerID sets the OwnerID field of the QueryFilter value.
+func (qf *QueryFilter) WithOwnerID(ownerID uuid.UUID) {
+	qf.OwnerID = &ownerID
+}
+
+// WithTitle sets the Title field of the QueryFilter value.
+func (qf *QueryFilter) WithTitle(title string) {
+	qf.Title = &title
+}
+
+// WithStartDate sets the StartDate field of the QueryFilter value.
+func (qf *QueryFilter) WithStartDate(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartDate = &d
+}
+
+// WithEndDate sets the EndDate field of the QueryFilter value.
+func (qf *QueryFilter) WithEndDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndDate = &d
+}
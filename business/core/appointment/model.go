@@ -0,0 +1,89 @@
+package appointment
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Appointment represents a meeting scheduled by a sales rep.
+type Appointment struct {
+	ID          uuid.UUID
+	OwnerID     uuid.UUID
+	Title       string
+	Description string
+	Location    string
+	Attendees   []mail.Address
+	TimeZone    string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Recurrence  Recurrence
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Duration returns the length of a single occurrence of the appointment.
+func (a Appointment) Duration() time.Duration {
+	return a.EndsAt.Sub(a.StartsAt)
+}
+
+// Loc returns the time zone the appointment was scheduled in. UTC is
+// returned if the time zone is unknown.
+func (a Appointment) Loc() *time.Location {
+	loc, err := time.LoadLocation(a.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Occurrences returns the start time of every occurrence of the appointment
+// that overlaps the window [from, to).
+func (a Appointment) Occurrences(from time.Time, to time.Time) []time.Time {
+	return a.Recurrence.Between(a.StartsAt.In(a.Loc()), a.Duration(), from, to)
+}
+
+// SeriesEndsAt returns the time the last occurrence of the appointment ends.
+// The boolean is false when the appointment recurs forever.
+func (a Appointment) SeriesEndsAt() (time.Time, bool) {
+	last, ok := a.Recurrence.last(a.StartsAt.In(a.Loc()))
+	if !ok {
+		return time.Time{}, false
+	}
+	return last.Add(a.Duration()), true
+}
+
+// NewAppointment contains information needed to create a new appointment.
+type NewAppointment struct {
+	OwnerID     uuid.UUID
+	Title       string
+	Description string
+	Location    string
+	Attendees   []mail.Address
+	TimeZone    string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Recurrence  Recurrence
+}
+
+// UpdateAppointment contains information needed to update an appointment.
+type UpdateAppointment struct {
+	Title       *string
+	Description *string
+	Location    *string
+	Attendees   []mail.Address
+	TimeZone    *string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Recurrence  *Recurrence
+}
+
+// Feed represents the secret a user subscribes to their calendar with. Only
+// the hash of the token is kept.
+type Feed struct {
+	OwnerID   uuid.UUID
+	TenantID  uuid.UUID
+	TokenHash string
+	CreatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package appointment
+
+import "sales-api/business/data/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByStartsAt, order.ASC)
+
+// Set of fields that the results can be ordered by. These are the names
+// that should be used by the application layer.
+const (
+	OrderByID       = "appointment_id"
+	OrderByTitle    = "title"
+	OrderByStartsAt = "starts_at"
+	OrderByEndsAt   = "ends_at"
+)
@@ -0,0 +1,384 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Set of supported recurrence frequencies.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+var frequencies = map[string]bool{
+	FreqDaily:   true,
+	FreqWeekly:  true,
+	FreqMonthly: true,
+	FreqYearly:  true,
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// untilLayout is the UTC date-time form of UNTIL used by RFC 5545.
+const untilLayout = "20060102T150405Z"
+
+// maxPeriods bounds how far a rule is expanded so a malformed or unbounded
+// rule can never spin forever. A rule with a count or until must end within
+// it, a rule that repeats forever is expanded that far from the window asked
+// for.
+const maxPeriods = 10000
+
+// Recurrence represents the subset of an iCalendar RRULE (RFC 5545) that
+// appointments support: FREQ, INTERVAL, COUNT, UNTIL and BYDAY on weekly
+// rules. The zero value means the appointment does not repeat.
+type Recurrence struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+// ParseRecurrence parses a rule in the form "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// An optional "RRULE:" prefix is accepted. An empty rule returns the zero
+// Recurrence.
+func ParseRecurrence(rule string) (Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return Recurrence{}, nil
+	}
+
+	r := Recurrence{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Recurrence{}, fmt.Errorf("malformed rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if !frequencies[r.Freq] {
+				return Recurrence{}, fmt.Errorf("unsupported frequency %q", value)
+			}
+
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Recurrence{}, fmt.Errorf("invalid interval %q", value)
+			}
+			r.Interval = n
+
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Recurrence{}, fmt.Errorf("invalid count %q", value)
+			}
+			r.Count = n
+
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return Recurrence{}, err
+			}
+			r.Until = until
+
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, exists := weekdays[strings.ToUpper(day)]
+				if !exists {
+					return Recurrence{}, fmt.Errorf("invalid day %q", day)
+				}
+				if !slices.Contains(r.ByDay, wd) {
+					r.ByDay = append(r.ByDay, wd)
+				}
+			}
+
+		default:
+			return Recurrence{}, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	switch {
+	case r.Freq == "":
+		return Recurrence{}, errors.New("missing frequency")
+	case r.Count > 0 && !r.Until.IsZero():
+		return Recurrence{}, errors.New("count and until are mutually exclusive")
+	case len(r.ByDay) > 0 && r.Freq != FreqWeekly:
+		return Recurrence{}, errors.New("byday is only supported on weekly rules")
+	}
+
+	// Weeks start on Monday (WKST=MO) so keep the days in that order.
+	slices.SortFunc(r.ByDay, func(a, b time.Weekday) int {
+		return mondayOffset(a) - mondayOffset(b)
+	})
+
+	return r, nil
+}
+
+// IsZero reports whether the appointment does not repeat.
+func (r Recurrence) IsZero() bool {
+	return r.Freq == ""
+}
+
+// String returns the rule in its RRULE value form.
+func (r Recurrence) String() string {
+	if r.IsZero() {
+		return ""
+	}
+
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = strings.ToUpper(wd.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Between returns the start of every occurrence that overlaps the window
+// [from, to). The start must be expressed in the appointment's time zone so
+// occurrences keep their wall clock time across daylight saving changes.
+func (r Recurrence) Between(start time.Time, duration time.Duration, from time.Time, to time.Time) []time.Time {
+	var starts []time.Time
+	r.each(start, from.Add(-duration), func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if t.Add(duration).After(from) {
+			starts = append(starts, t)
+		}
+		return true
+	})
+	return starts
+}
+
+// validate checks the first occurrence is one the rule would generate and
+// that a rule with a count or until ends within maxPeriods.
+func (r Recurrence) validate(start time.Time) error {
+	if len(r.ByDay) > 0 && !slices.Contains(r.ByDay, start.Weekday()) {
+		return fmt.Errorf("start day %s is not one of the recurrence days", start.Weekday())
+	}
+	if !r.Until.IsZero() && r.Until.Before(start) {
+		return errors.New("until is before the start")
+	}
+	if r.Count > 0 || !r.Until.IsZero() {
+		if !r.each(start, start, func(time.Time) bool { return true }) {
+			return fmt.Errorf("rule repeats for more than %d periods", maxPeriods)
+		}
+	}
+	return nil
+}
+
+// last returns the start of the final occurrence. The boolean is false when
+// the rule has neither a count nor an until and repeats forever.
+func (r Recurrence) last(start time.Time) (time.Time, bool) {
+	if !r.IsZero() && r.Count == 0 && r.Until.IsZero() {
+		return time.Time{}, false
+	}
+
+	last := start
+	r.each(start, start, func(t time.Time) bool {
+		last = t
+		return true
+	})
+	return last, true
+}
+
+// each calls fn with the start of every occurrence in order until the rule
+// is exhausted or fn returns false. Unless the rule has a count, occurrences
+// starting before from are skipped without expanding the periods they are
+// in. False is returned when the rule was cut off at maxPeriods.
+func (r Recurrence) each(start time.Time, from time.Time, fn func(time.Time) bool) bool {
+	if r.IsZero() {
+		fn(start)
+		return true
+	}
+
+	var emitted int
+	var done bool
+	emit := func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			done = true
+			return false
+		}
+		if r.Count == 0 && t.Before(from) {
+			return true
+		}
+		emitted++
+		if !fn(t) {
+			return false
+		}
+		if r.Count > 0 && emitted >= r.Count {
+			done = true
+			return false
+		}
+		return true
+	}
+
+	interval := max(r.Interval, 1)
+	y, m, d := start.Date()
+	hh, mm, ss := start.Clock()
+	loc := start.Location()
+
+	first := 0
+	if r.Count == 0 {
+		first = r.periodBefore(start, from)
+	}
+
+	for period := first; period < first+maxPeriods; period++ {
+		n := period * interval
+
+		switch r.Freq {
+		case FreqDaily:
+			if !emit(time.Date(y, m, d+n, hh, mm, ss, 0, loc)) {
+				return done
+			}
+
+		case FreqWeekly:
+			if len(r.ByDay) == 0 {
+				if !emit(time.Date(y, m, d+7*n, hh, mm, ss, 0, loc)) {
+					return done
+				}
+				continue
+			}
+
+			monday := d - mondayOffset(start.Weekday()) + 7*n
+			for _, wd := range r.ByDay {
+				t := time.Date(y, m, monday+mondayOffset(wd), hh, mm, ss, 0, loc)
+				if t.Before(start) {
+					continue
+				}
+				if !emit(t) {
+					return done
+				}
+			}
+
+		case FreqMonthly:
+			// Months without the start day, like the 31st, are skipped.
+			t := time.Date(y, m+time.Month(n), d, hh, mm, ss, 0, loc)
+			if t.Day() != d {
+				continue
+			}
+			if !emit(t) {
+				return done
+			}
+
+		case FreqYearly:
+			// Years without the start day, like February 29th, are skipped.
+			t := time.Date(y+n, m, d, hh, mm, ss, 0, loc)
+			if t.Day() != d {
+				continue
+			}
+			if !emit(t) {
+				return done
+			}
+
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// periodBefore returns the index of a period that starts no later than from,
+// close enough to it that expanding the rule from there is cheap. Periods
+// are counted from the one containing start.
+func (r Recurrence) periodBefore(start time.Time, from time.Time) int {
+	if !from.After(start) {
+		return 0
+	}
+	from = from.In(start.Location())
+
+	// Calendar dates are compared in UTC so daylight saving doesn't shift
+	// the number of days between them.
+	sy, sm, sd := start.Date()
+	fy, fm, fd := from.Date()
+	days := int(time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC).Sub(time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+
+	var units int
+	switch r.Freq {
+	case FreqDaily:
+		units = days
+	case FreqWeekly:
+		units = (days + mondayOffset(start.Weekday())) / 7
+	case FreqMonthly:
+		units = (fy-sy)*12 + int(fm-sm)
+	case FreqYearly:
+		units = fy - sy
+	}
+
+	// One period is given back for occurrences that began the period before
+	// and are still going at from.
+	return max(units/max(r.Interval, 1)-1, 0)
+}
+
+// =============================================================================
+
+// parseUntil accepts both the UTC date-time and the date form of UNTIL. A
+// date is inclusive so it is treated as the end of that day.
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse(untilLayout, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid until %q", value)
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// mondayOffset returns the number of days the weekday is after Monday.
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+// overlaps reports whether any occurrence in a overlaps any occurrence in b.
+// Both slices must be sorted.
+func overlaps(a []time.Time, aDuration time.Duration, b []time.Time, bDuration time.Duration) bool {
+	var i, j int
+	for i < len(a) && j < len(b) {
+		aEnd := a[i].Add(aDuration)
+		bEnd := b[j].Add(bDuration)
+
+		if a[i].Before(bEnd) && b[j].Before(aEnd) {
+			return true
+		}
+
+		if aEnd.Before(bEnd) {
+			i++
+			continue
+		}
+		j++
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package appointment
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurrenceLongRunning(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(1990, time.January, 3, 9, 0, 0, 0, loc)
+	from := time.Date(2026, time.March, 2, 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 14)
+
+	tt := []struct {
+		name  string
+		rule  string
+		first time.Time
+		count int
+	}{
+		{"daily", "FREQ=DAILY", time.Date(2026, time.March, 2, 9, 0, 0, 0, loc), 14},
+		{"every third day", "FREQ=DAILY;INTERVAL=3", time.Date(2026, time.March, 4, 9, 0, 0, 0, loc), 4},
+		{"weekly by day", "FREQ=WEEKLY;BYDAY=MO,WE", time.Date(2026, time.March, 2, 9, 0, 0, 0, loc), 4},
+		{"monthly", "FREQ=MONTHLY", time.Date(2026, time.March, 3, 9, 0, 0, 0, loc), 1},
+	}
+
+	for _, tst := range tt {
+		t.Run(tst.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tst.rule)
+			if err != nil {
+				t.Fatalf("Should be able to parse the rule : %s", err)
+			}
+			if err := r.validate(start); err != nil {
+				t.Fatalf("Should accept the rule : %s", err)
+			}
+
+			got := r.Between(start, time.Hour, from, to)
+			if len(got) != tst.count {
+				t.Fatalf("Should get %d occurrences, got %d : %v", tst.count, len(got), got)
+			}
+			if !got[0].Equal(tst.first) {
+				t.Errorf("Should start at %s, got %s", tst.first, got[0])
+			}
+
+			// Wall clock time is kept across daylight saving, which starts
+			// on March 8th 2026.
+			for _, occ := range got {
+				if occ.Hour() != 9 {
+					t.Errorf("Should occur at 9:00, got %s", occ)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceBounds(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name  string
+		rule  string
+		valid bool
+		last  time.Time
+	}{
+		{"daily for 20 years", "FREQ=DAILY;UNTIL=20451231T235959Z", true, time.Date(2045, time.December, 31, 9, 0, 0, 0, time.UTC)},
+		{"daily count at the cap", "FREQ=DAILY;COUNT=10000", true, start.AddDate(0, 0, 9999)},
+		{"daily for 30 years", "FREQ=DAILY;UNTIL=20551231T235959Z", false, time.Time{}},
+		{"daily count past the cap", "FREQ=DAILY;COUNT=10001", false, time.Time{}},
+		{"february 29th count past the cap", "FREQ=YEARLY;COUNT=2600", false, time.Time{}},
+	}
+
+	for _, tst := range tt {
+		t.Run(tst.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tst.rule)
+			if err != nil {
+				t.Fatalf("Should be able to parse the rule : %s", err)
+			}
+
+			s := start
+			if r.Freq == FreqYearly {
+				s = time.Date(2028, time.February, 29, 9, 0, 0, 0, time.UTC)
+			}
+
+			err = r.validate(s)
+			if !tst.valid {
+				if err == nil {
+					t.Fatal("Should refuse a rule that repeats past the cap")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Should accept the rule : %s", err)
+			}
+
+			last, ok := r.last(s)
+			if !ok || !last.Equal(tst.last) {
+				t.Errorf("Should end at %s, got %s", tst.last, last)
+			}
+		})
+	}
+}
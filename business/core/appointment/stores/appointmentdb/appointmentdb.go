@@ -0,0 +1,331 @@
+package appointmentdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/core/appointment"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/order"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresRepository struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+var _ appointment.Repository = (*PostgresRepository)(nil)
+
+func NewRepository(log *logger.Logger, db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+func (r *PostgresRepository) ExecuteUnderTransaction(tx transaction.Transaction) (appointment.Repository, error) {
+	ec, err := pgx.GetExtContext(tx)
+
+	if err != nil {
+		return nil, err
+	}
+	r = &PostgresRepository{
+		log: r.log,
+		db:  ec,
+	}
+	return r, nil
+}
+
+// Create inserts a new appointment into the database.
+func (r *PostgresRepository) Create(ctx context.Context, appt appointment.Appointment) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	INSERT INTO appointments
+		(appointment_id, tenant_id, owner_id, title, description, location, attendees, time_zone, starts_at, ends_at, recurrence, series_ends_at, created_at, updated_at)
+	VALUES
+		(:appointment_id, :tenant_id, :owner_id, :title, :description, :location, :attendees, :time_zone, :starts_at, :ends_at, :recurrence, :series_ends_at, :created_at, :updated_at)`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBAppointment(tenantID, appt)); err != nil {
+		if errors.Is(err, pgx.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", appointment.ErrOwnerNotFound)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Update replaces an appointment document in the database.
+func (r *PostgresRepository) Update(ctx context.Context, appt appointment.Appointment) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	UPDATE appointments
+	SET
+		"title" = :title,
+		"description" = :description,
+		"location" = :location,
+		"attendees" = :attendees,
+		"time_zone" = :time_zone,
+		"starts_at" = :starts_at,
+		"ends_at" = :ends_at,
+		"recurrence" = :recurrence,
+		"series_ends_at" = :series_ends_at,
+		"updated_at" = :updated_at
+	WHERE
+		appointment_id = :appointment_id AND tenant_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBAppointment(tenantID, appt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an appointment from the database.
+func (r *PostgresRepository) Delete(ctx context.Context, appointmentID uuid.UUID) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		ID       string `db:"appointment_id"`
+		TenantID string `db:"tenant_id"`
+	}{
+		ID:       appointmentID.String(),
+		TenantID: tenantID.String(),
+	}
+
+	const q = `
+	DELETE FROM appointments
+	WHERE
+		appointment_id = :appointment_id AND tenant_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Query retrieves a list of existing appointments from the database.
+func (r *PostgresRepository) Query(ctx context.Context, filter appointment.QueryFilter, orderBy order.By, page int, pageSize int) ([]appointment.Appointment, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"offset": (page - 1) * pageSize,
+		"limit":  pageSize,
+	}
+
+	const q = `
+	SELECT
+		appointment_id, owner_id, title, description, location, attendees, time_zone, starts_at, ends_at, recurrence, series_ends_at, created_at, updated_at
+	FROM
+		appointments`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :limit ROWS ONLY")
+
+	var dbAppts []dbAppointment
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, buf.String(), data, &dbAppts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreAppointmentSlice(dbAppts)
+}
+
+// Count returns the total number of appointments matching the filter.
+func (r *PostgresRepository) Count(ctx context.Context, filter appointment.QueryFilter) (int, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		appointments`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+	return count.Count, nil
+}
+
+// QueryByID gets the specified appointment from the database.
+func (r *PostgresRepository) QueryByID(ctx context.Context, appointmentID uuid.UUID) (appointment.Appointment, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return appointment.Appointment{}, err
+	}
+
+	data := struct {
+		ID       uuid.UUID `db:"appointment_id"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		ID:       appointmentID,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		appointment_id, owner_id, title, description, location, attendees, time_zone, starts_at, ends_at, recurrence, series_ends_at, created_at, updated_at
+	FROM
+		appointments
+	WHERE
+		appointment_id = :appointment_id AND tenant_id = :tenant_id`
+
+	var dbAppt dbAppointment
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dbAppt); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return appointment.Appointment{}, fmt.Errorf("namedquerystruct: %w", appointment.ErrNotFound)
+		}
+		return appointment.Appointment{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreAppointment(dbAppt)
+}
+
+// QueryByOwnerBetween retrieves every appointment of the owner that has an
+// occurrence inside the window [from, to).
+func (r *PostgresRepository) QueryByOwnerBetween(ctx context.Context, ownerID uuid.UUID, from time.Time, to time.Time) ([]appointment.Appointment, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter appointment.QueryFilter
+	filter.WithOwnerID(ownerID)
+	filter.WithStartDate(from)
+	filter.WithEndDate(to)
+
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		appointment_id, owner_id, title, description, location, attendees, time_zone, starts_at, ends_at, recurrence, series_ends_at, created_at, updated_at
+	FROM
+		appointments`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+	buf.WriteString(" ORDER BY starts_at ASC")
+
+	var dbAppts []dbAppointment
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, buf.String(), data, &dbAppts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreAppointmentSlice(dbAppts)
+}
+
+// =============================================================================
+
+// LockOwner takes a lock on the owner's appointments that is held until the
+// transaction ends. Outside of a transaction it is released right away.
+func (r *PostgresRepository) LockOwner(ctx context.Context, ownerID uuid.UUID) error {
+	data := struct {
+		OwnerID string `db:"owner_id"`
+	}{
+		OwnerID: ownerID.String(),
+	}
+
+	const q = `
+	SELECT pg_advisory_xact_lock(hashtextextended(:owner_id, 0))`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// UpsertFeed stores the calendar feed for the owner, replacing any previous
+// token.
+func (r *PostgresRepository) UpsertFeed(ctx context.Context, feed appointment.Feed) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The owner foreign key includes the tenant so a feed can't be created
+	// for a user of another organization.
+	const q = `
+	INSERT INTO appointment_feeds
+		(owner_id, tenant_id, token_hash, created_at)
+	VALUES
+		(:owner_id, :tenant_id, :token_hash, :created_at)
+	ON CONFLICT (owner_id) DO UPDATE SET
+		token_hash = EXCLUDED.token_hash,
+		created_at = EXCLUDED.created_at
+	WHERE
+		appointment_feeds.tenant_id = EXCLUDED.tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBFeed(tenantID, feed)); err != nil {
+		if errors.Is(err, pgx.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", appointment.ErrOwnerNotFound)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// QueryFeedByTokenHash gets the calendar feed with the specified token hash.
+// The token is the credential so the lookup isn't limited to a tenant; the
+// feed tells which tenant it belongs to.
+func (r *PostgresRepository) QueryFeedByTokenHash(ctx context.Context, tokenHash string) (appointment.Feed, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	SELECT
+		owner_id, tenant_id, token_hash, created_at
+	FROM
+		appointment_feeds
+	WHERE
+		token_hash = :token_hash`
+
+	var dbFd dbFeed
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dbFd); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return appointment.Feed{}, fmt.Errorf("namedquerystruct: %w", appointment.ErrFeedNotFound)
+		}
+		return appointment.Feed{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreFeed(dbFd), nil
+}
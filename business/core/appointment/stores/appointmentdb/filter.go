@@ -0,0 +1,44 @@
+package appointmentdb
+
+import (
+	"bytes"
+	"fmt"
+	"sales-api/business/core/appointment"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// applyFilter writes the WHERE clause for the filter. Rows are always limited
+// to the tenant, whatever the filter holds.
+func (r *PostgresRepository) applyFilter(tenantID uuid.UUID, filter appointment.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
+	data["tenant_id"] = tenantID
+	wc := []string{"tenant_id = :tenant_id"}
+
+	if filter.ID != nil {
+		data["appointment_id"] = *filter.ID
+		wc = append(wc, "appointment_id = :appointment_id")
+	}
+	if filter.OwnerID != nil {
+		data["owner_id"] = *filter.OwnerID
+		wc = append(wc, "owner_id = :owner_id")
+	}
+	if filter.Title != nil {
+		data["title"] = fmt.Sprintf("%%%s%%", *filter.Title)
+		wc = append(wc, "title LIKE :title")
+	}
+
+	// A series that repeats forever has no series_ends_at.
+	if filter.StartDate != nil {
+		data["start_date"] = *filter.StartDate
+		wc = append(wc, "(series_ends_at IS NULL OR series_ends_at > :start_date)")
+	}
+
+	if filter.EndDate != nil {
+		data["end_date"] = *filter.EndDate
+		wc = append(wc, "starts_at < :end_date")
+	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
+}
@@ -0,0 +1,141 @@
+package appointmentdb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"sales-api/business/core/appointment"
+	"sales-api/business/data/dbsql/pgx/dbarray"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dbAppointment represent the structure we need for moving data
+// between the app and the database.
+type dbAppointment struct {
+	ID           uuid.UUID      `db:"appointment_id"`
+	TenantID     uuid.UUID      `db:"tenant_id"`
+	OwnerID      uuid.UUID      `db:"owner_id"`
+	Title        string         `db:"title"`
+	Description  sql.NullString `db:"description"`
+	Location     sql.NullString `db:"location"`
+	Attendees    dbarray.String `db:"attendees"`
+	TimeZone     string         `db:"time_zone"`
+	StartsAt     time.Time      `db:"starts_at"`
+	EndsAt       time.Time      `db:"ends_at"`
+	Recurrence   sql.NullString `db:"recurrence"`
+	SeriesEndsAt sql.NullTime   `db:"series_ends_at"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
+
+func toDBAppointment(tenantID uuid.UUID, appt appointment.Appointment) dbAppointment {
+	attendees := make([]string, len(appt.Attendees))
+	for i, addr := range appt.Attendees {
+		attendees[i] = addr.String()
+	}
+
+	seriesEndsAt, ok := appt.SeriesEndsAt()
+
+	return dbAppointment{
+		ID:       appt.ID,
+		TenantID: tenantID,
+		OwnerID:  appt.OwnerID,
+		Title:    appt.Title,
+		Description: sql.NullString{
+			String: appt.Description,
+			Valid:  appt.Description != "",
+		},
+		Location: sql.NullString{
+			String: appt.Location,
+			Valid:  appt.Location != "",
+		},
+		Attendees: attendees,
+		TimeZone:  appt.TimeZone,
+		StartsAt:  appt.StartsAt.UTC(),
+		EndsAt:    appt.EndsAt.UTC(),
+		Recurrence: sql.NullString{
+			String: appt.Recurrence.String(),
+			Valid:  !appt.Recurrence.IsZero(),
+		},
+		SeriesEndsAt: sql.NullTime{
+			Time:  seriesEndsAt.UTC(),
+			Valid: ok,
+		},
+		CreatedAt: appt.CreatedAt.UTC(),
+		UpdatedAt: appt.UpdatedAt.UTC(),
+	}
+}
+
+func toCoreAppointment(dbAppt dbAppointment) (appointment.Appointment, error) {
+	attendees := make([]mail.Address, len(dbAppt.Attendees))
+	for i, value := range dbAppt.Attendees {
+		addr, err := mail.ParseAddress(value)
+		if err != nil {
+			return appointment.Appointment{}, fmt.Errorf("parse attendee: %w", err)
+		}
+		attendees[i] = *addr
+	}
+
+	recurrence, err := appointment.ParseRecurrence(dbAppt.Recurrence.String)
+	if err != nil {
+		return appointment.Appointment{}, fmt.Errorf("parse recurrence: %w", err)
+	}
+
+	appt := appointment.Appointment{
+		ID:          dbAppt.ID,
+		OwnerID:     dbAppt.OwnerID,
+		Title:       dbAppt.Title,
+		Description: dbAppt.Description.String,
+		Location:    dbAppt.Location.String,
+		Attendees:   attendees,
+		TimeZone:    dbAppt.TimeZone,
+		StartsAt:    dbAppt.StartsAt.In(time.Local),
+		EndsAt:      dbAppt.EndsAt.In(time.Local),
+		Recurrence:  recurrence,
+		CreatedAt:   dbAppt.CreatedAt.In(time.Local),
+		UpdatedAt:   dbAppt.UpdatedAt.In(time.Local),
+	}
+
+	return appt, nil
+}
+
+func toCoreAppointmentSlice(dbAppts []dbAppointment) ([]appointment.Appointment, error) {
+	appts := make([]appointment.Appointment, len(dbAppts))
+	for i, dbAppt := range dbAppts {
+		var err error
+		appts[i], err = toCoreAppointment(dbAppt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return appts, nil
+}
+
+// =============================================================================
+
+type dbFeed struct {
+	OwnerID   uuid.UUID `db:"owner_id"`
+	TenantID  uuid.UUID `db:"tenant_id"`
+	TokenHash string    `db:"token_hash"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBFeed(tenantID uuid.UUID, feed appointment.Feed) dbFeed {
+	return dbFeed{
+		OwnerID:   feed.OwnerID,
+		TenantID:  tenantID,
+		TokenHash: feed.TokenHash,
+		CreatedAt: feed.CreatedAt.UTC(),
+	}
+}
+
+func toCoreFeed(dbFd dbFeed) appointment.Feed {
+	return appointment.Feed{
+		OwnerID:   dbFd.OwnerID,
+		TenantID:  dbFd.TenantID,
+		TokenHash: dbFd.TokenHash,
+		CreatedAt: dbFd.CreatedAt.In(time.Local),
+	}
+}
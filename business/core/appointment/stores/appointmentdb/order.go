@@ -0,0 +1,22 @@
+package appointmentdb
+
+import (
+	"fmt"
+	"sales-api/business/core/appointment"
+	"sales-api/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	appointment.OrderByID:       "appointment_id",
+	appointment.OrderByTitle:    "title",
+	appointment.OrderByStartsAt: "starts_at",
+	appointment.OrderByEndsAt:   "ends_at",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,79 @@
+// Package decision provides the core business API for the log of
+// authorization decisions, which is kept for audits.
+package decision
+
+import (
+	"context"
+	"fmt"
+	"sales-api/business/data/order"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository interface declares the behavior this package needs to perists and
+// retrieve data.
+type Repository interface {
+	Create(ctx context.Context, d Decision) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page int, pageSize int) ([]Decision, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for decision access.
+type Core struct {
+	repository Repository
+	log        *logger.Logger
+}
+
+// NewCore constructs a core for decision api access.
+func NewCore(log *logger.Logger, repository Repository) *Core {
+	return &Core{
+		repository: repository,
+		log:        log,
+	}
+}
+
+// Record adds the decision to the log. It is called on every authorized
+// request and waits for the insert, which is the price of an audit log that
+// doesn't lose decisions.
+func (c *Core) Record(ctx context.Context, d Decision) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+
+	if err := c.repository.Create(ctx, d); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	return nil
+}
+
+// Query retrieves a list of decisions from the log.
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page int, pageSize int) ([]Decision, error) {
+	decisions, err := c.repository.Query(ctx, filter, orderBy, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return decisions, nil
+}
+
+// Count returns the total number of decisions.
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.repository.Count(ctx, filter)
+}
+
+// DeleteBefore removes the decisions made before the specified time, every
+// tenant's included. Decisions are kept for a retention period and are
+// removed from the maintenance connection.
+func (c *Core) DeleteBefore(ctx context.Context, before time.Time) error {
+	if err := c.repository.DeleteBefore(ctx, before); err != nil {
+		return fmt.Errorf("deletebefore: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package decision_test
+
+import (
+	"context"
+	"sales-api/business/core/decision"
+	"sales-api/business/core/decision/stores/decisiondb"
+	"sales-api/business/data/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type DecisionTestSuite struct {
+	suite.Suite
+	test *test.Test
+}
+
+func (s *DecisionTestSuite) SetupSuite() {
+	s.test = test.New(s.T())
+}
+func (s *DecisionTestSuite) TearDownSuite() {
+	s.test.TearDown()
+}
+
+// ==================================================
+
+func (suite *DecisionTestSuite) TestRecord() {
+	ctx := suite.test.Context()
+
+	subject := uuid.NewString()
+	target := uuid.New()
+
+	allowed := decision.Decision{
+		TenantID:      test.DefaultTenantID,
+		Subject:       subject,
+		Rule:          "ruleAdminOrSubject",
+		UserID:        target,
+		Route:         "GET /v1/users/{user_id}",
+		Allowed:       true,
+		PolicyVersion: "embedded",
+		Latency:       120 * time.Microsecond,
+	}
+	suite.NoError(suite.test.CoreAPIs.Decision.Record(ctx, allowed))
+
+	denied := allowed
+	denied.Rule = "ruleAdminOnly"
+	denied.UserID = uuid.Nil
+	denied.Route = "GET /v1/users"
+	denied.Allowed = false
+	denied.Reason = "rego evaluation failed"
+	suite.NoError(suite.test.CoreAPIs.Decision.Record(ctx, denied))
+
+	var filter decision.QueryFilter
+	filter.WithSubject(subject)
+
+	ds, err := suite.test.CoreAPIs.Decision.Query(ctx, filter, decision.DefaultOrderBy, 1, 10)
+	suite.NoError(err)
+	suite.Len(ds, 2)
+
+	count, err := suite.test.CoreAPIs.Decision.Count(ctx, filter)
+	suite.NoError(err)
+	suite.Equal(2, count)
+
+	filter.WithAllowed(false)
+	ds, err = suite.test.CoreAPIs.Decision.Query(ctx, filter, decision.DefaultOrderBy, 1, 10)
+	suite.NoError(err)
+	suite.Len(ds, 1)
+	suite.Equal(denied.Reason, ds[0].Reason)
+	suite.Equal(uuid.Nil, ds[0].UserID)
+	suite.Equal(denied.Latency, ds[0].Latency)
+
+	filter = decision.QueryFilter{}
+	filter.WithUserID(target)
+	ds, err = suite.test.CoreAPIs.Decision.Query(ctx, filter, decision.DefaultOrderBy, 1, 10)
+	suite.NoError(err)
+	suite.Len(ds, 1)
+	suite.True(ds[0].Allowed)
+}
+
+func (suite *DecisionTestSuite) TestDeleteBefore() {
+	ctx := suite.test.Context()
+
+	subject := uuid.NewString()
+
+	old := decision.Decision{
+		TenantID:      test.DefaultTenantID,
+		Subject:       subject,
+		Rule:          "ruleAny",
+		Route:         "GET /v1/organization",
+		Allowed:       true,
+		PolicyVersion: "embedded",
+		CreatedAt:     time.Now().Add(-48 * time.Hour),
+	}
+	suite.NoError(suite.test.CoreAPIs.Decision.Record(ctx, old))
+
+	recent := old
+	recent.CreatedAt = time.Now()
+	suite.NoError(suite.test.CoreAPIs.Decision.Record(ctx, recent))
+
+	// Old decisions are removed for every tenant, which takes the
+	// maintenance role.
+	purge := decision.NewCore(suite.test.Log, decisiondb.NewRepository(suite.test.Log, suite.test.MaintenanceDB))
+	suite.NoError(purge.DeleteBefore(context.Background(), time.Now().Add(-24*time.Hour)))
+
+	var filter decision.QueryFilter
+	filter.WithSubject(subject)
+
+	count, err := suite.test.CoreAPIs.Decision.Count(ctx, filter)
+	suite.NoError(err)
+	suite.Equal(1, count)
+}
+
+// ================================================
+func TestDecision(t *testing.T) {
+	suite.Run(t, new(DecisionTestSuite))
+}
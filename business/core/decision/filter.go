@@ -0,0 +1,59 @@
+package decision
+
+import (
+	"fmt"
+	"sales-api/foundation/validate"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+type QueryFilter struct {
+	Subject          *string    `validate:"omitempty"`
+	Rule             *string    `validate:"omitempty"`
+	UserID           *uuid.UUID `validate:"omitempty"`
+	Allowed          *bool      `validate:"omitempty"`
+	StartCreatedDate *time.Time `validate:"omitempty"`
+	EndCreatedDate   *time.Time `validate:"omitempty"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (qf *QueryFilter) Validate() error {
+	if err := validate.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// WithSubject sets the Subject field of the QueryFilter value.
+func (qf *QueryFilter) WithSubject(subject string) {
+	qf.Subject = &subject
+}
+
+// WithRule sets the Rule field of the QueryFilter value.
+func (qf *QueryFilter) WithRule(rule string) {
+	qf.Rule = &rule
+}
+
+// WithUserID sets the UserID field of the QueryFilter value.
+func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
+	qf.UserID = &userID
+}
+
+// WithAllowed sets the Allowed field of the QueryFilter value.
+func (qf *QueryFilter) WithAllowed(allowed bool) {
+	qf.Allowed = &allowed
+}
+
+// WithStartDateCreated sets the StartCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartCreatedDate = &d
+}
+
+// WithEndCreatedDate sets the EndCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndCreatedDate = &d
+}
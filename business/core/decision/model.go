@@ -0,0 +1,24 @@
+package decision
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Decision represents the outcome of authorizing a request against a rule.
+// UserID is the user the request targets, uuid.Nil when there is none. The
+// reason says why the request was denied.
+type Decision struct {
+	ID            uuid.UUID
+	TenantID      uuid.UUID
+	Subject       string
+	Rule          string
+	UserID        uuid.UUID
+	Route         string
+	Allowed       bool
+	Reason        string
+	PolicyVersion string
+	Latency       time.Duration
+	CreatedAt     time.Time
+}
@@ -0,0 +1,15 @@
+package decision
+
+import "sales-api/business/data/order"
+
+// DefaultOrderBy represents the default way we sort, the latest first.
+var DefaultOrderBy = order.NewBy(OrderByCreatedAt, order.DESC)
+
+// Set of fields that the results can be ordered by. These are the names
+// that should be used by the application layer.
+const (
+	OrderByCreatedAt = "created_at"
+	OrderBySubject   = "subject"
+	OrderByRule      = "rule"
+	OrderByLatency   = "latency"
+)
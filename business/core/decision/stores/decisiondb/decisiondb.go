@@ -0,0 +1,126 @@
+package decisiondb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sales-api/business/core/decision"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/order"
+	"sales-api/business/data/tenant"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresRepository manages the set of APIs for decision database access.
+// Decisions are recorded outside of the request transaction, so a request
+// that fails doesn't take its decision with it.
+type PostgresRepository struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+var _ decision.Repository = (*PostgresRepository)(nil)
+
+func NewRepository(log *logger.Logger, db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new decision into the database.
+func (r *PostgresRepository) Create(ctx context.Context, d decision.Decision) error {
+	const q = `
+	INSERT INTO authorization_decisions
+		(decision_id, tenant_id, subject, rule, user_id, route, allowed, reason, policy_version, latency_us, created_at)
+	VALUES
+		(:decision_id, :tenant_id, :subject, :rule, :user_id, :route, :allowed, :reason, :policy_version, :latency_us, :created_at)`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBDecision(d)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Query retrieves a list of existing decisions from the database.
+func (r *PostgresRepository) Query(ctx context.Context, filter decision.QueryFilter, orderBy order.By, page int, pageSize int) ([]decision.Decision, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"offset": (page - 1) * pageSize,
+		"limit":  pageSize,
+	}
+
+	const q = `
+	SELECT
+		decision_id, tenant_id, subject, rule, user_id, route, allowed, reason, policy_version, latency_us, created_at
+	FROM
+		authorization_decisions`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :limit ROWS ONLY")
+
+	var dbDs []dbDecision
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, buf.String(), data, &dbDs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreDecisionSlice(dbDs), nil
+}
+
+// Count returns the total number of decisions in the DB.
+func (r *PostgresRepository) Count(ctx context.Context, filter decision.QueryFilter) (int, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		authorization_decisions`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+	return count.Count, nil
+}
+
+// DeleteBefore removes the decisions created before the specified time.
+func (r *PostgresRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	data := struct {
+		Before time.Time `db:"before"`
+	}{
+		Before: before.UTC(),
+	}
+
+	const q = `
+	DELETE FROM authorization_decisions WHERE created_at < :before`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package decisiondb
+
+import (
+	"bytes"
+	"sales-api/business/core/decision"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// applyFilter writes the WHERE clause for the filter. Rows are always limited
+// to the tenant, whatever the filter holds.
+func (r *PostgresRepository) applyFilter(tenantID uuid.UUID, filter decision.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
+	data["tenant_id"] = tenantID
+	wc := []string{"tenant_id = :tenant_id"}
+
+	if filter.Subject != nil {
+		data["subject"] = *filter.Subject
+		wc = append(wc, "subject = :subject")
+	}
+	if filter.Rule != nil {
+		data["rule"] = *filter.Rule
+		wc = append(wc, "rule = :rule")
+	}
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+	if filter.Allowed != nil {
+		data["allowed"] = *filter.Allowed
+		wc = append(wc, "allowed = :allowed")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = *filter.StartCreatedDate
+		wc = append(wc, "created_at >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = *filter.EndCreatedDate
+		wc = append(wc, "created_at <= :end_date_created")
+	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
+}
@@ -0,0 +1,67 @@
+package decisiondb
+
+import (
+	"sales-api/business/core/decision"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dbDecision represent the structure we need for moving data
+// between the app and the database.
+type dbDecision struct {
+	ID            uuid.UUID     `db:"decision_id"`
+	TenantID      uuid.UUID     `db:"tenant_id"`
+	Subject       string        `db:"subject"`
+	Rule          string        `db:"rule"`
+	UserID        uuid.NullUUID `db:"user_id"`
+	Route         string        `db:"route"`
+	Allowed       bool          `db:"allowed"`
+	Reason        string        `db:"reason"`
+	PolicyVersion string        `db:"policy_version"`
+	LatencyUS     int64         `db:"latency_us"`
+	CreatedAt     time.Time     `db:"created_at"`
+}
+
+func toDBDecision(d decision.Decision) dbDecision {
+	return dbDecision{
+		ID:       d.ID,
+		TenantID: d.TenantID,
+		Subject:  d.Subject,
+		Rule:     d.Rule,
+		UserID: uuid.NullUUID{
+			UUID:  d.UserID,
+			Valid: d.UserID != uuid.Nil,
+		},
+		Route:         d.Route,
+		Allowed:       d.Allowed,
+		Reason:        d.Reason,
+		PolicyVersion: d.PolicyVersion,
+		LatencyUS:     d.Latency.Microseconds(),
+		CreatedAt:     d.CreatedAt.UTC(),
+	}
+}
+
+func toCoreDecision(dbD dbDecision) decision.Decision {
+	return decision.Decision{
+		ID:            dbD.ID,
+		TenantID:      dbD.TenantID,
+		Subject:       dbD.Subject,
+		Rule:          dbD.Rule,
+		UserID:        dbD.UserID.UUID,
+		Route:         dbD.Route,
+		Allowed:       dbD.Allowed,
+		Reason:        dbD.Reason,
+		PolicyVersion: dbD.PolicyVersion,
+		Latency:       time.Duration(dbD.LatencyUS) * time.Microsecond,
+		CreatedAt:     dbD.CreatedAt.In(time.Local),
+	}
+}
+
+func toCoreDecisionSlice(dbDs []dbDecision) []decision.Decision {
+	ds := make([]decision.Decision, len(dbDs))
+	for i, dbD := range dbDs {
+		ds[i] = toCoreDecision(dbD)
+	}
+	return ds
+}
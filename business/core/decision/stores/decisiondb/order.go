@@ -0,0 +1,22 @@
+package decisiondb
+
+import (
+	"fmt"
+	"sales-api/business/core/decision"
+	"sales-api/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	decision.OrderByCreatedAt: "created_at",
+	decision.OrderBySubject:   "subject",
+	decision.OrderByRule:      "rule",
+	decision.OrderByLatency:   "latency_us",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
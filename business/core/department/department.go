@@ -0,0 +1,256 @@
+// Package department provides the core business API for the departments of
+// the organization and the reporting lines between them.
+package department
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/core/user"
+	"sales-api/business/data/order"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound        = errors.New("department not found")
+	ErrUniqueName      = errors.New("name is not unique")
+	ErrManagerNotFound = errors.New("manager not found")
+	ErrParentNotFound  = errors.New("parent department not found")
+	ErrCycle           = errors.New("department can't be placed below itself")
+)
+
+// maxDepth bounds how many levels of parent departments are followed.
+const maxDepth = 32
+
+// Repository interface declares the behavior this package needs to perists and
+// retrieve data.
+type Repository interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Repository, error)
+	Create(ctx context.Context, dep Department) error
+	Update(ctx context.Context, dep Department) error
+	Delete(ctx context.Context, departmentID uuid.UUID) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page int, pageSize int) ([]Department, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, departmentID uuid.UUID) (Department, error)
+	QueryAll(ctx context.Context) ([]Department, error)
+	QueryMembers(ctx context.Context) ([]Member, error)
+	QueryReportingChain(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for department access.
+type Core struct {
+	repository Repository
+	usrCore    *user.Core
+	log        *logger.Logger
+}
+
+// NewCore constructs a core for department api access.
+func NewCore(log *logger.Logger, usrCore *user.Core, repository Repository) *Core {
+	return &Core{
+		repository: repository,
+		usrCore:    usrCore,
+		log:        log,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	trs, err := c.repository.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		repository: trs,
+		usrCore:    usrCore,
+		log:        c.log,
+	}
+
+	return c, nil
+}
+
+// Create adds a new department to the organization.
+func (c *Core) Create(ctx context.Context, nd NewDepartment) (Department, error) {
+	now := time.Now()
+
+	dep := Department{
+		ID:        uuid.New(),
+		Name:      nd.Name,
+		ManagerID: nd.ManagerID,
+		ParentID:  nd.ParentID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := c.validate(ctx, dep); err != nil {
+		return Department{}, err
+	}
+
+	if err := c.repository.Create(ctx, dep); err != nil {
+		return Department{}, fmt.Errorf("create: %w", err)
+	}
+
+	return dep, nil
+}
+
+// Update modifies information about a department.
+func (c *Core) Update(ctx context.Context, dep Department, ud UpdateDepartment) (Department, error) {
+	if ud.Name != nil {
+		dep.Name = *ud.Name
+	}
+	if ud.ManagerID != nil {
+		dep.ManagerID = *ud.ManagerID
+	}
+	if ud.ParentID != nil {
+		dep.ParentID = *ud.ParentID
+	}
+
+	dep.UpdatedAt = time.Now()
+
+	if err := c.validate(ctx, dep); err != nil {
+		return Department{}, err
+	}
+
+	if err := c.repository.Update(ctx, dep); err != nil {
+		return Department{}, fmt.Errorf("update: %w", err)
+	}
+
+	return dep, nil
+}
+
+// Delete removes the specified department. Its users and sub-departments are
+// left without a department and parent respectively.
+func (c *Core) Delete(ctx context.Context, departmentID uuid.UUID) error {
+	if err := c.repository.Delete(ctx, departmentID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// QueryByID returns the department by its ID,
+// returns "ErrNotFound" if the department record is not found
+func (c *Core) QueryByID(ctx context.Context, departmentID uuid.UUID) (Department, error) {
+	dep, err := c.repository.QueryByID(ctx, departmentID)
+	if err != nil {
+		return Department{}, fmt.Errorf("query: department_id[%s]: %w", departmentID, err)
+	}
+	return dep, nil
+}
+
+// Query retrieves a list of existing departments.
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page int, pageSize int) ([]Department, error) {
+	deps, err := c.repository.Query(ctx, filter, orderBy, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return deps, nil
+}
+
+// Count returns the total number of departments.
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.repository.Count(ctx, filter)
+}
+
+// ============================================================================
+
+// OrgChart returns the organization as a tree of departments. Departments
+// without a parent are the roots.
+func (c *Core) OrgChart(ctx context.Context) ([]Node, error) {
+	deps, err := c.repository.QueryAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("queryall: %w", err)
+	}
+
+	members, err := c.repository.QueryMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querymembers: %w", err)
+	}
+
+	membersByDep := make(map[uuid.UUID][]Member)
+	for _, mem := range members {
+		membersByDep[mem.DepartmentID] = append(membersByDep[mem.DepartmentID], mem)
+	}
+
+	childrenByDep := make(map[uuid.UUID][]Department)
+	for _, dep := range deps {
+		childrenByDep[dep.ParentID] = append(childrenByDep[dep.ParentID], dep)
+	}
+
+	var build func(dep Department, depth int) Node
+	build = func(dep Department, depth int) Node {
+		node := Node{
+			Department: dep,
+			Members:    membersByDep[dep.ID],
+		}
+		if depth < maxDepth {
+			for _, child := range childrenByDep[dep.ID] {
+				node.Children = append(node.Children, build(child, depth+1))
+			}
+		}
+		return node
+	}
+
+	var roots []Node
+	for _, dep := range childrenByDep[uuid.Nil] {
+		roots = append(roots, build(dep, 1))
+	}
+
+	return roots, nil
+}
+
+// ReportingChain returns the managers the user reports to, starting with the
+// manager of their own department and walking up the parent departments.
+func (c *Core) ReportingChain(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	chain, err := c.repository.QueryReportingChain(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("queryreportingchain: user_id[%s]: %w", userID, err)
+	}
+	return chain, nil
+}
+
+// ============================================================================
+
+// validate applies the business rules to a department before it is stored.
+func (c *Core) validate(ctx context.Context, dep Department) error {
+	if dep.ManagerID != uuid.Nil {
+		if _, err := c.usrCore.QueryByID(ctx, dep.ManagerID); err != nil {
+			if errors.Is(err, user.ErrNotFound) {
+				return ErrManagerNotFound
+			}
+			return fmt.Errorf("querybyid: manager_id[%s]: %w", dep.ManagerID, err)
+		}
+	}
+
+	// Walk up from the new parent to make sure the department doesn't end up
+	// below itself.
+	parentID := dep.ParentID
+	for depth := 0; parentID != uuid.Nil; depth++ {
+		if parentID == dep.ID || depth == maxDepth {
+			return ErrCycle
+		}
+
+		parent, err := c.repository.QueryByID(ctx, parentID)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return ErrParentNotFound
+			}
+			return fmt.Errorf("querybyid: parent_id[%s]: %w", parentID, err)
+		}
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
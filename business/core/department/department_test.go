@@ -0,0 +1,89 @@
+package department_test
+
+import (
+	"net/mail"
+	"sales-api/business/core/department"
+	"sales-api/business/core/user"
+	"sales-api/business/data/test"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type DepartmentTestSuite struct {
+	suite.Suite
+	test *test.Test
+}
+
+func (s *DepartmentTestSuite) SetupSuite() {
+	s.test = test.New(s.T())
+}
+func (s *DepartmentTestSuite) TearDownSuite() {
+	s.test.TearDown()
+}
+
+// ==================================================
+
+func (suite *DepartmentTestSuite) TestReportingChain() {
+	ctx := suite.test.Context()
+
+	vp := suite.createUser("vp@gmail.com", uuid.Nil)
+	lead := suite.createUser("lead@gmail.com", uuid.Nil)
+
+	sales := suite.createDepartment(department.NewDepartment{Name: "Sales", ManagerID: vp.ID})
+	emea := suite.createDepartment(department.NewDepartment{Name: "Sales EMEA", ManagerID: lead.ID, ParentID: sales.ID})
+
+	rep := suite.createUser("rep@gmail.com", emea.ID)
+
+	chain, err := suite.test.CoreAPIs.Department.ReportingChain(ctx, rep.ID)
+	suite.NoError(err)
+	suite.Equal([]uuid.UUID{lead.ID, vp.ID}, chain)
+
+	// A department can't be moved below one of its own sub-departments.
+	_, err = suite.test.CoreAPIs.Department.Update(ctx, sales, department.UpdateDepartment{ParentID: &emea.ID})
+	suite.ErrorIs(err, department.ErrCycle)
+
+	nodes, err := suite.test.CoreAPIs.Department.OrgChart(ctx)
+	suite.NoError(err)
+	suite.Len(nodes, 1)
+	suite.Equal(sales.ID, nodes[0].Department.ID)
+	suite.Len(nodes[0].Children, 1)
+	suite.Len(nodes[0].Children[0].Members, 1)
+	suite.Equal(rep.ID, nodes[0].Children[0].Members[0].UserID)
+}
+
+func (suite *DepartmentTestSuite) TestManagerNotFound() {
+	_, err := suite.test.CoreAPIs.Department.Create(suite.test.Context(), department.NewDepartment{
+		Name:      "Support",
+		ManagerID: uuid.New(),
+	})
+	suite.ErrorIs(err, department.ErrManagerNotFound)
+}
+
+func (suite *DepartmentTestSuite) createDepartment(nd department.NewDepartment) department.Department {
+	dep, err := suite.test.CoreAPIs.Department.Create(suite.test.Context(), nd)
+	suite.NoError(err)
+	suite.Equal(nd.Name, dep.Name)
+	return dep
+}
+
+func (suite *DepartmentTestSuite) createUser(address string, departmentID uuid.UUID) user.User {
+	email, err := mail.ParseAddress(address)
+	suite.NoError(err)
+
+	usr, err := suite.test.CoreAPIs.User.Create(suite.test.Context(), user.NewUser{
+		Name:         address,
+		Email:        *email,
+		Roles:        []user.Role{user.RoleUser},
+		DepartmentID: departmentID,
+		Password:     "password",
+	})
+	suite.NoError(err)
+	return usr
+}
+
+// ================================================
+func TestDepartment(t *testing.T) {
+	suite.Run(t, new(DepartmentTestSuite))
+}
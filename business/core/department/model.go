@@ -0,0 +1,48 @@
+package department
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Department represents a unit of the organization. A uuid.Nil ManagerID or
+// ParentID means the department has no manager or is at the top of the
+// organization.
+type Department struct {
+	ID        uuid.UUID
+	Name      string
+	ManagerID uuid.UUID
+	ParentID  uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewDepartment contains information needed to create a new department.
+type NewDepartment struct {
+	Name      string
+	ManagerID uuid.UUID
+	ParentID  uuid.UUID
+}
+
+// UpdateDepartment contains information needed to update a department.
+type UpdateDepartment struct {
+	Name      *string
+	ManagerID *uuid.UUID
+	ParentID  *uuid.UUID
+}
+
+// Member represents a user as they appear in the org chart.
+type Member struct {
+	UserID       uuid.UUID
+	Name         string
+	DepartmentID uuid.UUID
+}
+
+// Node represents a department in the org chart along with the users in it
+// and the departments below it.
+type Node struct {
+	Department Department
+	Members    []Member
+	Children   []Node
+}
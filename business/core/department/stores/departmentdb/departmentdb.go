@@ -0,0 +1,336 @@
+package departmentdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/core/department"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/order"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresRepository struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+var _ department.Repository = (*PostgresRepository)(nil)
+
+func NewRepository(log *logger.Logger, db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+func (r *PostgresRepository) ExecuteUnderTransaction(tx transaction.Transaction) (department.Repository, error) {
+	ec, err := pgx.GetExtContext(tx)
+
+	if err != nil {
+		return nil, err
+	}
+	r = &PostgresRepository{
+		log: r.log,
+		db:  ec,
+	}
+	return r, nil
+}
+
+// Create inserts a new department into the database.
+func (r *PostgresRepository) Create(ctx context.Context, dep department.Department) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	INSERT INTO departments
+		(department_id, tenant_id, name, manager_id, parent_id, created_at, updated_at)
+	VALUES
+		(:department_id, :tenant_id, :name, :manager_id, :parent_id, :created_at, :updated_at)`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBDepartment(tenantID, dep)); err != nil {
+		if errors.Is(err, pgx.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", department.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a department document in the database.
+func (r *PostgresRepository) Update(ctx context.Context, dep department.Department) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	UPDATE departments
+	SET
+		"name" = :name,
+		"manager_id" = :manager_id,
+		"parent_id" = :parent_id,
+		"updated_at" = :updated_at
+	WHERE
+		department_id = :department_id AND tenant_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBDepartment(tenantID, dep)); err != nil {
+		if errors.Is(err, pgx.ErrDBDuplicatedEntry) {
+			return department.ErrUniqueName
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a department from the database.
+func (r *PostgresRepository) Delete(ctx context.Context, departmentID uuid.UUID) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		ID       string `db:"department_id"`
+		TenantID string `db:"tenant_id"`
+	}{
+		ID:       departmentID.String(),
+		TenantID: tenantID.String(),
+	}
+
+	const q = `
+	DELETE FROM departments
+	WHERE
+		department_id = :department_id AND tenant_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Query retrieves a list of existing departments from the database.
+func (r *PostgresRepository) Query(ctx context.Context, filter department.QueryFilter, orderBy order.By, page int, pageSize int) ([]department.Department, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"offset": (page - 1) * pageSize,
+		"limit":  pageSize,
+	}
+
+	const q = `
+	SELECT
+		department_id, name, manager_id, parent_id, created_at, updated_at
+	FROM
+		departments`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :limit ROWS ONLY")
+
+	var dbDeps []dbDepartment
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, buf.String(), data, &dbDeps); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreDepartmentSlice(dbDeps), nil
+}
+
+// Count returns the total number of departments matching the filter.
+func (r *PostgresRepository) Count(ctx context.Context, filter department.QueryFilter) (int, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		departments`
+
+	buf := bytes.NewBufferString(q)
+	r.applyFilter(tenantID, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+	return count.Count, nil
+}
+
+// QueryByID gets the specified department from the database.
+func (r *PostgresRepository) QueryByID(ctx context.Context, departmentID uuid.UUID) (department.Department, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return department.Department{}, err
+	}
+
+	data := struct {
+		ID       uuid.UUID `db:"department_id"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		ID:       departmentID,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		department_id, name, manager_id, parent_id, created_at, updated_at
+	FROM
+		departments
+	WHERE
+		department_id = :department_id AND tenant_id = :tenant_id`
+
+	var dbDep dbDepartment
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dbDep); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return department.Department{}, fmt.Errorf("namedquerystruct: %w", department.ErrNotFound)
+		}
+		return department.Department{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreDepartment(dbDep), nil
+}
+
+// QueryAll retrieves every department from the database.
+func (r *PostgresRepository) QueryAll(ctx context.Context) ([]department.Department, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		department_id, name, manager_id, parent_id, created_at, updated_at
+	FROM
+		departments
+	WHERE
+		tenant_id = :tenant_id
+	ORDER BY
+		name`
+
+	var dbDeps []dbDepartment
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, q, data, &dbDeps); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreDepartmentSlice(dbDeps), nil
+}
+
+// QueryMembers retrieves every user that belongs to a department.
+func (r *PostgresRepository) QueryMembers(ctx context.Context) ([]department.Member, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		user_id, name, department_id
+	FROM
+		users
+	WHERE
+		tenant_id = :tenant_id AND department_id IS NOT NULL
+	ORDER BY
+		name`
+
+	var dbMems []dbMember
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, q, data, &dbMems); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreMemberSlice(dbMems), nil
+}
+
+// QueryReportingChain retrieves the managers above the user, nearest first,
+// by following the user's department up through its parents.
+func (r *PostgresRepository) QueryReportingChain(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		UserID   uuid.UUID `db:"user_id"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		UserID:   userID,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	WITH RECURSIVE chain AS (
+		SELECT
+			d.department_id, d.manager_id, d.parent_id, 1 AS depth
+		FROM
+			departments AS d
+		JOIN
+			users AS u ON u.department_id = d.department_id
+		WHERE
+			u.user_id = :user_id AND u.tenant_id = :tenant_id
+		UNION ALL
+		SELECT
+			p.department_id, p.manager_id, p.parent_id, c.depth + 1
+		FROM
+			departments AS p
+		JOIN
+			chain AS c ON p.department_id = c.parent_id AND p.tenant_id = :tenant_id
+		WHERE
+			c.depth < 32
+	)
+	SELECT
+		manager_id
+	FROM
+		chain
+	WHERE
+		manager_id IS NOT NULL AND manager_id <> :user_id
+	ORDER BY
+		depth`
+
+	var dbChain []struct {
+		ManagerID uuid.UUID `db:"manager_id"`
+	}
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, q, data, &dbChain); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	chain := make([]uuid.UUID, len(dbChain))
+	for i, link := range dbChain {
+		chain[i] = link.ManagerID
+	}
+	return chain, nil
+}
@@ -0,0 +1,37 @@
+package departmentdb
+
+import (
+	"bytes"
+	"fmt"
+	"sales-api/business/core/department"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// applyFilter writes the WHERE clause for the filter. Rows are always limited
+// to the tenant, whatever the filter holds.
+func (r *PostgresRepository) applyFilter(tenantID uuid.UUID, filter department.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
+	data["tenant_id"] = tenantID
+	wc := []string{"tenant_id = :tenant_id"}
+
+	if filter.ID != nil {
+		data["department_id"] = *filter.ID
+		wc = append(wc, "department_id = :department_id")
+	}
+	if filter.Name != nil {
+		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
+		wc = append(wc, "name LIKE :name")
+	}
+	if filter.ManagerID != nil {
+		data["manager_id"] = *filter.ManagerID
+		wc = append(wc, "manager_id = :manager_id")
+	}
+	if filter.ParentID != nil {
+		data["parent_id"] = *filter.ParentID
+		wc = append(wc, "parent_id = :parent_id")
+	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
+}
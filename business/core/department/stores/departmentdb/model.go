@@ -0,0 +1,77 @@
+package departmentdb
+
+import (
+	"sales-api/business/core/department"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dbDepartment represent the structure we need for moving data
+// between the app and the database.
+type dbDepartment struct {
+	ID        uuid.UUID     `db:"department_id"`
+	TenantID  uuid.UUID     `db:"tenant_id"`
+	Name      string        `db:"name"`
+	ManagerID uuid.NullUUID `db:"manager_id"`
+	ParentID  uuid.NullUUID `db:"parent_id"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+func toDBDepartment(tenantID uuid.UUID, dep department.Department) dbDepartment {
+	return dbDepartment{
+		ID:       dep.ID,
+		TenantID: tenantID,
+		Name:     dep.Name,
+		ManagerID: uuid.NullUUID{
+			UUID:  dep.ManagerID,
+			Valid: dep.ManagerID != uuid.Nil,
+		},
+		ParentID: uuid.NullUUID{
+			UUID:  dep.ParentID,
+			Valid: dep.ParentID != uuid.Nil,
+		},
+		CreatedAt: dep.CreatedAt.UTC(),
+		UpdatedAt: dep.UpdatedAt.UTC(),
+	}
+}
+
+func toCoreDepartment(dbDep dbDepartment) department.Department {
+	return department.Department{
+		ID:        dbDep.ID,
+		Name:      dbDep.Name,
+		ManagerID: dbDep.ManagerID.UUID,
+		ParentID:  dbDep.ParentID.UUID,
+		CreatedAt: dbDep.CreatedAt.In(time.Local),
+		UpdatedAt: dbDep.UpdatedAt.In(time.Local),
+	}
+}
+
+func toCoreDepartmentSlice(dbDeps []dbDepartment) []department.Department {
+	deps := make([]department.Department, len(dbDeps))
+	for i, dbDep := range dbDeps {
+		deps[i] = toCoreDepartment(dbDep)
+	}
+	return deps
+}
+
+// =============================================================================
+
+type dbMember struct {
+	UserID       uuid.UUID `db:"user_id"`
+	Name         string    `db:"name"`
+	DepartmentID uuid.UUID `db:"department_id"`
+}
+
+func toCoreMemberSlice(dbMems []dbMember) []department.Member {
+	mems := make([]department.Member, len(dbMems))
+	for i, dbMem := range dbMems {
+		mems[i] = department.Member{
+			UserID:       dbMem.UserID,
+			Name:         dbMem.Name,
+			DepartmentID: dbMem.DepartmentID,
+		}
+	}
+	return mems
+}
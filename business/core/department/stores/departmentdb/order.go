@@ -0,0 +1,20 @@
+package departmentdb
+
+import (
+	"fmt"
+	"sales-api/business/core/department"
+	"sales-api/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	department.OrderByID:   "department_id",
+	department.OrderByName: "name",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,26 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization represents a company hosted by the service. Its ID is the
+// tenant every user and their data belongs to.
+type Organization struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewOrganization contains information needed to create a new organization.
+type NewOrganization struct {
+	Name string
+}
+
+// UpdateOrganization contains information needed to update an organization.
+type UpdateOrganization struct {
+	Name *string
+}
@@ -0,0 +1,104 @@
+// Package organization provides the core business API for the organizations
+// hosted by the service. Each organization is a tenant; nothing is shared
+// between them.
+package organization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound = errors.New("organization not found")
+)
+
+// Repository interface declares the behavior this package needs to perists and
+// retrieve data.
+type Repository interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Repository, error)
+	Create(ctx context.Context, org Organization) error
+	Update(ctx context.Context, org Organization) error
+	QueryByID(ctx context.Context, organizationID uuid.UUID) (Organization, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for organization access.
+type Core struct {
+	repository Repository
+	log        *logger.Logger
+}
+
+// NewCore constructs a core for organization api access.
+func NewCore(log *logger.Logger, repository Repository) *Core {
+	return &Core{
+		repository: repository,
+		log:        log,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	trs, err := c.repository.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		repository: trs,
+		log:        c.log,
+	}
+
+	return c, nil
+}
+
+// Create adds a new organization.
+func (c *Core) Create(ctx context.Context, no NewOrganization) (Organization, error) {
+	now := time.Now()
+
+	org := Organization{
+		ID:        uuid.New(),
+		Name:      no.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := c.repository.Create(ctx, org); err != nil {
+		return Organization{}, fmt.Errorf("create: %w", err)
+	}
+
+	return org, nil
+}
+
+// Update modifies information about an organization.
+func (c *Core) Update(ctx context.Context, org Organization, uo UpdateOrganization) (Organization, error) {
+	if uo.Name != nil {
+		org.Name = *uo.Name
+	}
+
+	org.UpdatedAt = time.Now()
+
+	if err := c.repository.Update(ctx, org); err != nil {
+		return Organization{}, fmt.Errorf("update: %w", err)
+	}
+
+	return org, nil
+}
+
+// QueryByID returns the organization by its ID,
+// returns "ErrNotFound" if the organization record is not found
+func (c *Core) QueryByID(ctx context.Context, organizationID uuid.UUID) (Organization, error) {
+	org, err := c.repository.QueryByID(ctx, organizationID)
+	if err != nil {
+		return Organization{}, fmt.Errorf("query: organization_id[%s]: %w", organizationID, err)
+	}
+	return org, nil
+}
@@ -0,0 +1,35 @@
+package organizationdb
+
+import (
+	"sales-api/business/core/organization"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dbOrganization represent the structure we need for moving data
+// between the app and the database.
+type dbOrganization struct {
+	ID        uuid.UUID `db:"organization_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBOrganization(org organization.Organization) dbOrganization {
+	return dbOrganization{
+		ID:        org.ID,
+		Name:      org.Name,
+		CreatedAt: org.CreatedAt.UTC(),
+		UpdatedAt: org.UpdatedAt.UTC(),
+	}
+}
+
+func toCoreOrganization(dbOrg dbOrganization) organization.Organization {
+	return organization.Organization{
+		ID:        dbOrg.ID,
+		Name:      dbOrg.Name,
+		CreatedAt: dbOrg.CreatedAt.In(time.Local),
+		UpdatedAt: dbOrg.UpdatedAt.In(time.Local),
+	}
+}
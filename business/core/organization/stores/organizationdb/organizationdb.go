@@ -0,0 +1,122 @@
+package organizationdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/core/organization"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresRepository struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+var _ organization.Repository = (*PostgresRepository)(nil)
+
+func NewRepository(log *logger.Logger, db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+func (r *PostgresRepository) ExecuteUnderTransaction(tx transaction.Transaction) (organization.Repository, error) {
+	ec, err := pgx.GetExtContext(tx)
+
+	if err != nil {
+		return nil, err
+	}
+	r = &PostgresRepository{
+		log: r.log,
+		db:  ec,
+	}
+	return r, nil
+}
+
+// Create inserts a new organization into the database. A new organization
+// has no tenant yet so this is the one write that isn't scoped to one.
+func (r *PostgresRepository) Create(ctx context.Context, org organization.Organization) error {
+	const q = `
+	INSERT INTO organizations
+		(organization_id, name, created_at, updated_at)
+	VALUES
+		(:organization_id, :name, :created_at, :updated_at)`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBOrganization(org)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Update replaces an organization document in the database. Only the tenant's
+// own organization can be changed.
+func (r *PostgresRepository) Update(ctx context.Context, org organization.Organization) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		dbOrganization
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		dbOrganization: toDBOrganization(org),
+		TenantID:       tenantID,
+	}
+
+	const q = `
+	UPDATE organizations
+	SET
+		"name" = :name,
+		"updated_at" = :updated_at
+	WHERE
+		organization_id = :organization_id AND organization_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// QueryByID gets the specified organization from the database. Only the
+// tenant's own organization can be found.
+func (r *PostgresRepository) QueryByID(ctx context.Context, organizationID uuid.UUID) (organization.Organization, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return organization.Organization{}, err
+	}
+
+	data := struct {
+		ID       uuid.UUID `db:"organization_id"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		ID:       organizationID,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		organization_id, name, created_at, updated_at
+	FROM
+		organizations
+	WHERE
+		organization_id = :organization_id AND organization_id = :tenant_id`
+
+	var dbOrg dbOrganization
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dbOrg); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return organization.Organization{}, fmt.Errorf("namedquerystruct: %w", organization.ErrNotFound)
+		}
+		return organization.Organization{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreOrganization(dbOrg), nil
+}
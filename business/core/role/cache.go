@@ -0,0 +1,129 @@
+package role
+
+import (
+	"context"
+	"fmt"
+	"sales-api/business/core/user"
+	"sales-api/business/data/tenant"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cacheTTL is how long the roles are kept in memory before they are read
+// again. Changes made by other instances show up after this long.
+const cacheTTL = 30 * time.Second
+
+// Cache holds the permissions of the roles of every organization. It reads
+// them with its own repository, never within a request's transaction.
+type Cache struct {
+	repository Repository
+	loadMu     sync.Mutex
+	mu         sync.RWMutex
+	builtIn    map[string][]string
+	tenants    map[uuid.UUID]map[string][]string
+	loadedAt   time.Time
+}
+
+// NewCache constructs a cache that reads the roles through the repository.
+// The repository has to see the roles of every organization.
+func NewCache(repository Repository) *Cache {
+	return &Cache{
+		repository: repository,
+	}
+}
+
+// Load reads the roles of every organization from the database, making them
+// known to user.ParseRole and replacing the cached permissions.
+func (c *Cache) Load(ctx context.Context) error {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	return c.load(ctx)
+}
+
+// load does the work of Load, the caller holds loadMu.
+func (c *Cache) load(ctx context.Context) error {
+	rols, err := c.repository.QueryAllTenants(ctx)
+	if err != nil {
+		return fmt.Errorf("queryalltenants: %w", err)
+	}
+
+	names := make([]string, len(rols))
+	builtIn := make(map[string][]string)
+	tenants := make(map[uuid.UUID]map[string][]string)
+	for i, rol := range rols {
+		names[i] = rol.Name
+
+		perms := make([]string, len(rol.Permissions))
+		for j, perm := range rol.Permissions {
+			perms[j] = perm.Name()
+		}
+
+		if rol.TenantID == uuid.Nil {
+			builtIn[rol.Name] = perms
+			continue
+		}
+		if tenants[rol.TenantID] == nil {
+			tenants[rol.TenantID] = make(map[string][]string)
+		}
+		tenants[rol.TenantID][rol.Name] = perms
+	}
+
+	// Every organization sees the built-in roles next to its own.
+	for _, perms := range tenants {
+		for name, builtInPerms := range builtIn {
+			perms[name] = builtInPerms
+		}
+	}
+
+	user.SetRoles(names)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.builtIn = builtIn
+	c.tenants = tenants
+	c.loadedAt = time.Now()
+
+	return nil
+}
+
+// Permissions returns the names of the permissions granted by each role of
+// the organization, keyed by role name. The roles are read again once the
+// cache expires.
+func (c *Cache) Permissions(ctx context.Context) (map[string][]string, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only one request reloads an expired cache, the others wait for it and
+	// find it fresh.
+	if !c.fresh() {
+		c.loadMu.Lock()
+		if !c.fresh() {
+			if err := c.load(ctx); err != nil {
+				c.loadMu.Unlock()
+				return nil, err
+			}
+		}
+		c.loadMu.Unlock()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if perms, exists := c.tenants[tenantID]; exists {
+		return perms, nil
+	}
+	return c.builtIn, nil
+}
+
+// fresh reports whether the roles were loaded within the last cacheTTL.
+func (c *Cache) fresh() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.builtIn != nil && time.Since(c.loadedAt) < cacheTTL
+}
@@ -0,0 +1,33 @@
+package role
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role represents a named set of permissions that can be given to users.
+// Built-in roles are shared by every organization and can't be changed or
+// removed; they have no TenantID.
+type Role struct {
+	TenantID    uuid.UUID
+	Name        string
+	Description string
+	Permissions []Permission
+	BuiltIn     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewRole contains information needed to create a new role.
+type NewRole struct {
+	Name        string
+	Description string
+	Permissions []Permission
+}
+
+// UpdateRole contains information needed to update a role.
+type UpdateRole struct {
+	Description *string
+	Permissions []Permission
+}
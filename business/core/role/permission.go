@@ -0,0 +1,58 @@
+package role
+
+import "fmt"
+
+// Set of permissions a role can grant. The rules in authorization.rego are
+// written in terms of these names.
+var (
+	PermissionAdmin   = Permission{"admin"}
+	PermissionSelf    = Permission{"self"}
+	PermissionReports = Permission{"reports"}
+)
+
+// Set of known permissions.
+var permissions = map[string]Permission{
+	PermissionAdmin.name:   PermissionAdmin,
+	PermissionSelf.name:    PermissionSelf,
+	PermissionReports.name: PermissionReports,
+}
+
+// Permission represents something a role allows its users to do.
+type Permission struct {
+	name string
+}
+
+// ParsePermission parses the string value and returns a permission if one
+// exists.
+func ParsePermission(value string) (Permission, error) {
+	perm, exists := permissions[value]
+	if !exists {
+		return Permission{}, fmt.Errorf("invalid permission %q", value)
+	}
+	return perm, nil
+}
+
+// Name returns the name of the permission.
+func (p Permission) Name() string {
+	return p.name
+}
+
+// MarshalText implement the marshal interface for JSON conversions.
+func (p Permission) MarshalText() ([]byte, error) {
+	return []byte(p.name), nil
+}
+
+// UnmarshalText implement the unmarshal interface for JSON conversions.
+func (p *Permission) UnmarshalText(data []byte) error {
+	perm, err := ParsePermission(string(data))
+	if err != nil {
+		return err
+	}
+	p.name = perm.name
+	return nil
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (p Permission) Equal(p2 Permission) bool {
+	return p.name == p2.name
+}
@@ -0,0 +1,204 @@
+// Package role provides the core business API for the roles users can hold
+// and the permissions each role grants.
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound    = errors.New("role not found")
+	ErrUniqueName  = errors.New("name is not unique")
+	ErrInvalidName = errors.New("name must be upper case letters, digits and underscores")
+	ErrBuiltIn     = errors.New("built-in roles can't be changed")
+	ErrInUse       = errors.New("role is held by users")
+)
+
+var validName = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,62}$`)
+
+// Repository interface declares the behavior this package needs to perists and
+// retrieve data.
+type Repository interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Repository, error)
+	Create(ctx context.Context, rol Role) error
+	Update(ctx context.Context, rol Role) error
+	Delete(ctx context.Context, name string) error
+	QueryByName(ctx context.Context, name string) (Role, error)
+	QueryAll(ctx context.Context) ([]Role, error)
+	QueryAllTenants(ctx context.Context) ([]Role, error)
+	CountHolders(ctx context.Context, name string) (int, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for role access.
+type Core struct {
+	repository Repository
+	cache      *Cache
+	log        *logger.Logger
+}
+
+// NewCore constructs a core for role api access. Every core of the service
+// should share the same cache, so a change made through one is seen by all
+// of them.
+func NewCore(log *logger.Logger, cache *Cache, repository Repository) *Core {
+	return &Core{
+		repository: repository,
+		cache:      cache,
+		log:        log,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	trs, err := c.repository.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		repository: trs,
+		cache:      c.cache,
+		log:        c.log,
+	}
+
+	return c, nil
+}
+
+// Create adds a new role to the organization.
+func (c *Core) Create(ctx context.Context, nr NewRole) (Role, error) {
+	if !validName.MatchString(nr.Name) {
+		return Role{}, ErrInvalidName
+	}
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// The database only keeps names unique within an organization, a role
+	// can't take the name of a built-in one either.
+	switch _, err := c.repository.QueryByName(ctx, nr.Name); {
+	case err == nil:
+		return Role{}, ErrUniqueName
+	case !errors.Is(err, ErrNotFound):
+		return Role{}, fmt.Errorf("querybyname: name[%s]: %w", nr.Name, err)
+	}
+
+	now := time.Now()
+
+	rol := Role{
+		TenantID:    tenantID,
+		Name:        nr.Name,
+		Description: nr.Description,
+		Permissions: nr.Permissions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := c.repository.Create(ctx, rol); err != nil {
+		return Role{}, fmt.Errorf("create: %w", err)
+	}
+
+	c.reload(ctx)
+
+	return rol, nil
+}
+
+// Update modifies the description and permissions of a role.
+func (c *Core) Update(ctx context.Context, rol Role, ur UpdateRole) (Role, error) {
+	if rol.BuiltIn {
+		return Role{}, ErrBuiltIn
+	}
+
+	if ur.Description != nil {
+		rol.Description = *ur.Description
+	}
+	if ur.Permissions != nil {
+		rol.Permissions = ur.Permissions
+	}
+
+	rol.UpdatedAt = time.Now()
+
+	if err := c.repository.Update(ctx, rol); err != nil {
+		return Role{}, fmt.Errorf("update: %w", err)
+	}
+
+	c.reload(ctx)
+
+	return rol, nil
+}
+
+// Delete removes the specified role. Roles still held by users can't be
+// removed.
+func (c *Core) Delete(ctx context.Context, rol Role) error {
+	if rol.BuiltIn {
+		return ErrBuiltIn
+	}
+
+	holders, err := c.repository.CountHolders(ctx, rol.Name)
+	if err != nil {
+		return fmt.Errorf("countholders: %w", err)
+	}
+	if holders > 0 {
+		return ErrInUse
+	}
+
+	if err := c.repository.Delete(ctx, rol.Name); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	c.reload(ctx)
+
+	return nil
+}
+
+// QueryByName returns the role by its name,
+// returns "ErrNotFound" if the role record is not found
+func (c *Core) QueryByName(ctx context.Context, name string) (Role, error) {
+	rol, err := c.repository.QueryByName(ctx, name)
+	if err != nil {
+		return Role{}, fmt.Errorf("query: name[%s]: %w", name, err)
+	}
+	return rol, nil
+}
+
+// QueryAll retrieves every role ordered by name.
+func (c *Core) QueryAll(ctx context.Context) ([]Role, error) {
+	rols, err := c.repository.QueryAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("queryall: %w", err)
+	}
+	return rols, nil
+}
+
+// Load reads the roles of every organization into the cache, making them
+// known to user.ParseRole.
+func (c *Core) Load(ctx context.Context) error {
+	return c.cache.Load(ctx)
+}
+
+// Permissions returns the names of the permissions granted by each role of
+// the organization, keyed by role name.
+func (c *Core) Permissions(ctx context.Context) (map[string][]string, error) {
+	return c.cache.Permissions(ctx)
+}
+
+// reload reads the roles again once the change is committed, reading them
+// before would put back what is about to change.
+func (c *Core) reload(ctx context.Context) {
+	transaction.AfterCommit(ctx, func() {
+		if err := c.cache.Load(ctx); err != nil {
+			c.log.Error(ctx, "role: reloading roles", "ERROR", err)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package role_test
+
+import (
+	"net/mail"
+	"sales-api/business/core/role"
+	"sales-api/business/core/user"
+	"sales-api/business/data/test"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleTestSuite struct {
+	suite.Suite
+	test *test.Test
+}
+
+func (s *RoleTestSuite) SetupSuite() {
+	s.test = test.New(s.T())
+}
+func (s *RoleTestSuite) TearDownSuite() {
+	s.test.TearDown()
+}
+
+// ==================================================
+
+func (suite *RoleTestSuite) TestCRUD() {
+	ctx := suite.test.Context()
+
+	nr := role.NewRole{
+		Name:        "SALES_LEAD",
+		Description: "Leads a sales team",
+		Permissions: []role.Permission{role.PermissionSelf, role.PermissionReports},
+	}
+	rol, err := suite.test.CoreAPIs.Role.Create(ctx, nr)
+	suite.NoError(err)
+	suite.Equal(nr.Name, rol.Name)
+
+	// New roles are known to users without a restart.
+	usrRole, err := user.ParseRole("SALES_LEAD")
+	suite.NoError(err)
+
+	perms, err := suite.test.CoreAPIs.Role.Permissions(ctx)
+	suite.NoError(err)
+	suite.Equal([]string{"self", "reports"}, perms["SALES_LEAD"])
+
+	_, err = suite.test.CoreAPIs.Role.Create(ctx, nr)
+	suite.ErrorIs(err, role.ErrUniqueName)
+
+	rol, err = suite.test.CoreAPIs.Role.Update(ctx, rol, role.UpdateRole{Permissions: []role.Permission{role.PermissionSelf}})
+	suite.NoError(err)
+	suite.Len(rol.Permissions, 1)
+
+	// A role held by a user can't be removed.
+	email, err := mail.ParseAddress("lead@gmail.com")
+	suite.NoError(err)
+	usr, err := suite.test.CoreAPIs.User.Create(ctx, user.NewUser{
+		Name:     "Lead",
+		Email:    *email,
+		Roles:    []user.Role{usrRole},
+		Password: "password",
+	})
+	suite.NoError(err)
+
+	suite.ErrorIs(suite.test.CoreAPIs.Role.Delete(ctx, rol), role.ErrInUse)
+
+	suite.NoError(suite.test.CoreAPIs.User.Delete(ctx, usr.ID))
+	suite.NoError(suite.test.CoreAPIs.Role.Delete(ctx, rol))
+
+	_, err = user.ParseRole("SALES_LEAD")
+	suite.Error(err)
+}
+
+func (suite *RoleTestSuite) TestBuiltIn() {
+	ctx := suite.test.Context()
+
+	admin, err := suite.test.CoreAPIs.Role.QueryByName(ctx, user.RoleAdmin.Name())
+	suite.NoError(err)
+	suite.True(admin.BuiltIn)
+
+	suite.ErrorIs(suite.test.CoreAPIs.Role.Delete(ctx, admin), role.ErrBuiltIn)
+
+	_, err = suite.test.CoreAPIs.Role.Create(ctx, role.NewRole{Name: "sales"})
+	suite.ErrorIs(err, role.ErrInvalidName)
+}
+
+// ================================================
+func TestRole(t *testing.T) {
+	suite.Run(t, new(RoleTestSuite))
+}
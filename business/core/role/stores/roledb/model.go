@@ -0,0 +1,76 @@
+package roledb
+
+import (
+	"fmt"
+	"sales-api/business/core/role"
+	"sales-api/business/data/dbsql/pgx/dbarray"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dbRole represent the structure we need for moving data
+// between the app and the database.
+type dbRole struct {
+	TenantID    uuid.NullUUID  `db:"tenant_id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Permissions dbarray.String `db:"permissions"`
+	BuiltIn     bool           `db:"built_in"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+func toDBRole(tenantID uuid.UUID, rol role.Role) dbRole {
+	perms := make([]string, len(rol.Permissions))
+	for i, perm := range rol.Permissions {
+		perms[i] = perm.Name()
+	}
+	return dbRole{
+		TenantID: uuid.NullUUID{
+			UUID:  tenantID,
+			Valid: tenantID != uuid.Nil,
+		},
+		Name:        rol.Name,
+		Description: rol.Description,
+		Permissions: perms,
+		BuiltIn:     rol.BuiltIn,
+		CreatedAt:   rol.CreatedAt.UTC(),
+		UpdatedAt:   rol.UpdatedAt.UTC(),
+	}
+}
+
+func toCoreRole(dbRol dbRole) (role.Role, error) {
+	perms := make([]role.Permission, len(dbRol.Permissions))
+	for i, value := range dbRol.Permissions {
+		var err error
+		perms[i], err = role.ParsePermission(value)
+		if err != nil {
+			return role.Role{}, fmt.Errorf("parse permission: %w", err)
+		}
+	}
+
+	rol := role.Role{
+		TenantID:    dbRol.TenantID.UUID,
+		Name:        dbRol.Name,
+		Description: dbRol.Description,
+		Permissions: perms,
+		BuiltIn:     dbRol.BuiltIn,
+		CreatedAt:   dbRol.CreatedAt.In(time.Local),
+		UpdatedAt:   dbRol.UpdatedAt.In(time.Local),
+	}
+
+	return rol, nil
+}
+
+func toCoreRoleSlice(dbRoles []dbRole) ([]role.Role, error) {
+	rols := make([]role.Role, len(dbRoles))
+	for i, dbRol := range dbRoles {
+		var err error
+		rols[i], err = toCoreRole(dbRol)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rols, nil
+}
@@ -0,0 +1,232 @@
+package roledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/core/role"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/tenant"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresRepository struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+var _ role.Repository = (*PostgresRepository)(nil)
+
+func NewRepository(log *logger.Logger, db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+func (r *PostgresRepository) ExecuteUnderTransaction(tx transaction.Transaction) (role.Repository, error) {
+	ec, err := pgx.GetExtContext(tx)
+
+	if err != nil {
+		return nil, err
+	}
+	r = &PostgresRepository{
+		log: r.log,
+		db:  ec,
+	}
+	return r, nil
+}
+
+// Create inserts a new role for the tenant into the database.
+func (r *PostgresRepository) Create(ctx context.Context, rol role.Role) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	INSERT INTO roles
+		(tenant_id, name, description, permissions, built_in, created_at, updated_at)
+	VALUES
+		(:tenant_id, :name, :description, :permissions, :built_in, :created_at, :updated_at)`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBRole(tenantID, rol)); err != nil {
+		if errors.Is(err, pgx.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", role.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a role document of the tenant in the database. Built-in
+// roles belong to no tenant so they are never touched.
+func (r *PostgresRepository) Update(ctx context.Context, rol role.Role) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	UPDATE roles
+	SET
+		"description" = :description,
+		"permissions" = :permissions,
+		"updated_at" = :updated_at
+	WHERE
+		name = :name AND tenant_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBRole(tenantID, rol)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a role of the tenant from the database.
+func (r *PostgresRepository) Delete(ctx context.Context, name string) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		Name     string    `db:"name"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		Name:     name,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	DELETE FROM roles
+	WHERE
+		name = :name AND tenant_id = :tenant_id`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// QueryByName gets the specified role from the built-in roles and those of
+// the tenant.
+func (r *PostgresRepository) QueryByName(ctx context.Context, name string) (role.Role, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return role.Role{}, err
+	}
+
+	data := struct {
+		Name     string    `db:"name"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		Name:     name,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		tenant_id, name, description, permissions, built_in, created_at, updated_at
+	FROM
+		roles
+	WHERE
+		name = :name AND (tenant_id = :tenant_id OR tenant_id IS NULL)`
+
+	var dbRol dbRole
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dbRol); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return role.Role{}, fmt.Errorf("namedquerystruct: %w", role.ErrNotFound)
+		}
+		return role.Role{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreRole(dbRol)
+}
+
+// QueryAll retrieves the built-in roles and those of the tenant from the
+// database.
+func (r *PostgresRepository) QueryAll(ctx context.Context) ([]role.Role, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		tenant_id, name, description, permissions, built_in, created_at, updated_at
+	FROM
+		roles
+	WHERE
+		tenant_id = :tenant_id OR tenant_id IS NULL
+	ORDER BY
+		name`
+
+	var dbRoles []dbRole
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, q, data, &dbRoles); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreRoleSlice(dbRoles)
+}
+
+// QueryAllTenants retrieves the roles of every tenant from the database. It
+// only feeds the in-memory cache of permissions, which keeps them apart by
+// tenant.
+func (r *PostgresRepository) QueryAllTenants(ctx context.Context) ([]role.Role, error) {
+	const q = `
+	SELECT
+		tenant_id, name, description, permissions, built_in, created_at, updated_at
+	FROM
+		roles
+	ORDER BY
+		name`
+
+	var dbRoles []dbRole
+	if err := pgx.NamedQuerySlice(ctx, r.log, r.db, q, struct{}{}, &dbRoles); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreRoleSlice(dbRoles)
+}
+
+// CountHolders returns the number of users of the tenant that hold the role.
+func (r *PostgresRepository) CountHolders(ctx context.Context, name string) (int, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	data := struct {
+		Name     string    `db:"name"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		Name:     name,
+		TenantID: tenantID,
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		users
+	WHERE
+		tenant_id = :tenant_id AND :name = ANY(roles)`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+	return count.Count, nil
+}
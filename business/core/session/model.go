@@ -0,0 +1,28 @@
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken represents one refresh token handed to a client. Every token
+// rotated from the same login shares the session id. Only a hash of the
+// token is kept.
+type RefreshToken struct {
+	ID        uuid.UUID
+	SessionID uuid.UUID
+	TenantID  uuid.UUID
+	UserID    uuid.UUID
+	Hash      []byte
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	RevokedAt time.Time
+	CreatedAt time.Time
+}
+
+// Spent reports whether the token was already exchanged or revoked, either
+// of which means it can't be used again.
+func (rt RefreshToken) Spent() bool {
+	return !rt.UsedAt.IsZero() || !rt.RevokedAt.IsZero()
+}
@@ -0,0 +1,218 @@
+// Package session provides the core business API for login sessions. A
+// session hands out rotating refresh tokens and keeps track of the access
+// tokens that were revoked before they expired.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for session operations.
+var (
+	ErrNotFound = errors.New("refresh token not found")
+	ErrExpired  = errors.New("refresh token expired")
+	ErrReused   = errors.New("refresh token reused")
+)
+
+// refreshTTL is how long a refresh token can be exchanged for a new one.
+const refreshTTL = 30 * 24 * time.Hour
+
+// Repository interface declares the behavior this package needs to perists and
+// retrieve data.
+type Repository interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Repository, error)
+	Create(ctx context.Context, rt RefreshToken) error
+	QueryByHash(ctx context.Context, hash []byte) (RefreshToken, error)
+	Exchange(ctx context.Context, tokenID uuid.UUID, usedAt time.Time, next RefreshToken) error
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error
+	RevokeAccess(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessRevoked(ctx context.Context, jti string, sessionID uuid.UUID) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for session access.
+type Core struct {
+	repository Repository
+	log        *logger.Logger
+}
+
+// NewCore constructs a core for session api access.
+func NewCore(log *logger.Logger, repository Repository) *Core {
+	return &Core{
+		repository: repository,
+		log:        log,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	trs, err := c.repository.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		repository: trs,
+		log:        c.log,
+	}
+
+	return c, nil
+}
+
+// Start begins a new session for the user and returns its first refresh
+// token. Access tokens carry the session id so ending the session revokes
+// them as well.
+func (c *Core) Start(ctx context.Context, tenantID uuid.UUID, userID uuid.UUID) (string, RefreshToken, error) {
+	value, rt, err := newRefreshToken(uuid.New(), tenantID, userID, time.Now())
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+
+	if err := c.repository.Create(ctx, rt); err != nil {
+		return "", RefreshToken{}, fmt.Errorf("create: %w", err)
+	}
+
+	return value, rt, nil
+}
+
+// Rotate exchanges a refresh token for a new one in the same session. A
+// token can only be exchanged once; presenting it again means it was copied,
+// so the whole session is revoked and ErrReused is returned.
+func (c *Core) Rotate(ctx context.Context, refreshToken string) (string, RefreshToken, error) {
+	rt, err := c.repository.QueryByHash(ctx, hash(refreshToken))
+	if err != nil {
+		return "", RefreshToken{}, fmt.Errorf("querybyhash: %w", err)
+	}
+
+	now := time.Now()
+
+	if rt.Spent() {
+		return "", RefreshToken{}, c.reused(ctx, rt, now)
+	}
+
+	if now.After(rt.ExpiresAt) {
+		return "", RefreshToken{}, ErrExpired
+	}
+
+	value, next, err := newRefreshToken(rt.SessionID, rt.TenantID, rt.UserID, now)
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+
+	// The token is marked used and the next one stored together, so a
+	// failure leaves the token to be retried. Two requests racing with the
+	// same token both get past the check above, the store only lets one of
+	// them exchange it.
+	if err := c.repository.Exchange(ctx, rt.ID, now, next); err != nil {
+		if errors.Is(err, ErrReused) {
+			return "", RefreshToken{}, c.reused(ctx, rt, now)
+		}
+		return "", RefreshToken{}, fmt.Errorf("exchange: %w", err)
+	}
+
+	return value, rt, nil
+}
+
+// End revokes the session the refresh token belongs to, the access tokens
+// issued in it included. The token must have been issued to the specified
+// user.
+func (c *Core) End(ctx context.Context, refreshToken string, userID uuid.UUID) error {
+	rt, err := c.repository.QueryByHash(ctx, hash(refreshToken))
+	if err != nil {
+		return fmt.Errorf("querybyhash: %w", err)
+	}
+
+	if rt.UserID != userID {
+		return ErrNotFound
+	}
+
+	if err := c.repository.RevokeSession(ctx, rt.SessionID, time.Now()); err != nil {
+		return fmt.Errorf("revokesession: session_id[%s]: %w", rt.SessionID, err)
+	}
+
+	return nil
+}
+
+// RevokeAccess stops an access token from being accepted before it expires.
+func (c *Core) RevokeAccess(ctx context.Context, jti string, expiresAt time.Time) error {
+	if err := c.repository.RevokeAccess(ctx, jti, expiresAt); err != nil {
+		return fmt.Errorf("revokeaccess: jti[%s]: %w", jti, err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the refresh tokens and the access token revocations
+// of every tenant that have expired. It is meant to run periodically rather
+// than within a request.
+func (c *Core) DeleteExpired(ctx context.Context) error {
+	if err := c.repository.DeleteExpired(ctx, time.Now()); err != nil {
+		return fmt.Errorf("deleteexpired: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the access token with the specified id was
+// revoked, or the session it was issued in was.
+func (c *Core) IsRevoked(ctx context.Context, jti string, sessionID uuid.UUID) (bool, error) {
+	revoked, err := c.repository.IsAccessRevoked(ctx, jti, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("isaccessrevoked: jti[%s] session_id[%s]: %w", jti, sessionID, err)
+	}
+	return revoked, nil
+}
+
+// =============================================================================
+
+// newRefreshToken generates a refresh token in the session and returns its
+// value. Only the hash is stored so the value can't be recovered from the
+// database.
+func newRefreshToken(sessionID uuid.UUID, tenantID uuid.UUID, userID uuid.UUID, now time.Time) (string, RefreshToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", RefreshToken{}, fmt.Errorf("generating token: %w", err)
+	}
+	value := base64.RawURLEncoding.EncodeToString(b)
+
+	rt := RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		TenantID:  tenantID,
+		UserID:    userID,
+		Hash:      hash(value),
+		ExpiresAt: now.Add(refreshTTL),
+		CreatedAt: now,
+	}
+
+	return value, rt, nil
+}
+
+// reused revokes the session of a token that was presented more than once.
+func (c *Core) reused(ctx context.Context, rt RefreshToken, now time.Time) error {
+	c.log.Info(ctx, "session: refresh token reused", "session_id", rt.SessionID, "user_id", rt.UserID)
+
+	if err := c.repository.RevokeSession(ctx, rt.SessionID, now); err != nil {
+		return fmt.Errorf("revokesession: session_id[%s]: %w", rt.SessionID, err)
+	}
+
+	return ErrReused
+}
+
+func hash(value string) []byte {
+	sum := sha256.Sum256([]byte(value))
+	return sum[:]
+}
@@ -0,0 +1,116 @@
+package session_test
+
+import (
+	"net/mail"
+	"sales-api/business/core/session"
+	"sales-api/business/data/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionTestSuite struct {
+	suite.Suite
+	test *test.Test
+}
+
+func (s *SessionTestSuite) SetupSuite() {
+	s.test = test.New(s.T())
+}
+func (s *SessionTestSuite) TearDownSuite() {
+	s.test.TearDown()
+}
+
+// ==================================================
+
+func (suite *SessionTestSuite) TestRotate() {
+	ctx := suite.test.Context()
+
+	email, err := mail.ParseAddress("user@example.com")
+	suite.NoError(err)
+	usr, err := suite.test.CoreAPIs.User.QueryByEmail(ctx, *email)
+	suite.NoError(err)
+
+	first, _, err := suite.test.CoreAPIs.Session.Start(ctx, usr.TenantID, usr.ID)
+	suite.NoError(err)
+
+	second, rt, err := suite.test.CoreAPIs.Session.Rotate(ctx, first)
+	suite.NoError(err)
+	suite.NotEqual(first, second)
+	suite.Equal(usr.ID, rt.UserID)
+	suite.Equal(usr.TenantID, rt.TenantID)
+
+	// Using the first token again gives the session away.
+	_, _, err = suite.test.CoreAPIs.Session.Rotate(ctx, first)
+	suite.ErrorIs(err, session.ErrReused)
+
+	_, _, err = suite.test.CoreAPIs.Session.Rotate(ctx, second)
+	suite.ErrorIs(err, session.ErrReused)
+
+	_, _, err = suite.test.CoreAPIs.Session.Rotate(ctx, "not-a-token")
+	suite.ErrorIs(err, session.ErrNotFound)
+}
+
+func (suite *SessionTestSuite) TestLogout() {
+	ctx := suite.test.Context()
+
+	email, err := mail.ParseAddress("admin@example.com")
+	suite.NoError(err)
+	usr, err := suite.test.CoreAPIs.User.QueryByEmail(ctx, *email)
+	suite.NoError(err)
+
+	refresh, rt, err := suite.test.CoreAPIs.Session.Start(ctx, usr.TenantID, usr.ID)
+	suite.NoError(err)
+
+	// Access tokens issued in the session are revoked with it.
+	revoked, err := suite.test.CoreAPIs.Session.IsRevoked(ctx, uuid.NewString(), rt.SessionID)
+	suite.NoError(err)
+	suite.False(revoked)
+
+	suite.NoError(suite.test.CoreAPIs.Session.End(ctx, refresh, usr.ID))
+
+	revoked, err = suite.test.CoreAPIs.Session.IsRevoked(ctx, uuid.NewString(), rt.SessionID)
+	suite.NoError(err)
+	suite.True(revoked)
+
+	_, _, err = suite.test.CoreAPIs.Session.Rotate(ctx, refresh)
+	suite.ErrorIs(err, session.ErrReused)
+
+	const jti = "2f0a6c1e-51a4-4d0e-9f3c-7b9a1d2e4c55"
+
+	revoked, err = suite.test.CoreAPIs.Session.IsRevoked(ctx, jti, uuid.Nil)
+	suite.NoError(err)
+	suite.False(revoked)
+
+	suite.NoError(suite.test.CoreAPIs.Session.RevokeAccess(ctx, jti, time.Now().Add(time.Hour)))
+
+	revoked, err = suite.test.CoreAPIs.Session.IsRevoked(ctx, jti, uuid.Nil)
+	suite.NoError(err)
+	suite.True(revoked)
+
+	// Revoking doesn't clean up, the periodic purge does, and only what has
+	// expired.
+	const expiredJTI = "8c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+	suite.NoError(suite.test.CoreAPIs.Session.RevokeAccess(ctx, expiredJTI, time.Now().Add(-time.Minute)))
+
+	revoked, err = suite.test.CoreAPIs.Session.IsRevoked(ctx, expiredJTI, uuid.Nil)
+	suite.NoError(err)
+	suite.True(revoked)
+
+	suite.NoError(suite.test.CoreAPIs.Session.DeleteExpired(ctx))
+
+	revoked, err = suite.test.CoreAPIs.Session.IsRevoked(ctx, expiredJTI, uuid.Nil)
+	suite.NoError(err)
+	suite.False(revoked)
+
+	revoked, err = suite.test.CoreAPIs.Session.IsRevoked(ctx, jti, uuid.Nil)
+	suite.NoError(err)
+	suite.True(revoked)
+}
+
+// ================================================
+func TestSession(t *testing.T) {
+	suite.Run(t, new(SessionTestSuite))
+}
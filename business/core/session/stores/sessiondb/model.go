@@ -0,0 +1,62 @@
+package sessiondb
+
+import (
+	"database/sql"
+	"sales-api/business/core/session"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dbRefreshToken represent the structure we need for moving data
+// between the app and the database.
+type dbRefreshToken struct {
+	ID        uuid.UUID    `db:"token_id"`
+	SessionID uuid.UUID    `db:"session_id"`
+	TenantID  uuid.UUID    `db:"tenant_id"`
+	UserID    uuid.UUID    `db:"user_id"`
+	Hash      []byte       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+func toDBRefreshToken(rt session.RefreshToken) dbRefreshToken {
+	return dbRefreshToken{
+		ID:        rt.ID,
+		SessionID: rt.SessionID,
+		TenantID:  rt.TenantID,
+		UserID:    rt.UserID,
+		Hash:      rt.Hash,
+		ExpiresAt: rt.ExpiresAt.UTC(),
+		UsedAt: sql.NullTime{
+			Time:  rt.UsedAt.UTC(),
+			Valid: !rt.UsedAt.IsZero(),
+		},
+		RevokedAt: sql.NullTime{
+			Time:  rt.RevokedAt.UTC(),
+			Valid: !rt.RevokedAt.IsZero(),
+		},
+		CreatedAt: rt.CreatedAt.UTC(),
+	}
+}
+
+func toCoreRefreshToken(dbRT dbRefreshToken) session.RefreshToken {
+	rt := session.RefreshToken{
+		ID:        dbRT.ID,
+		SessionID: dbRT.SessionID,
+		TenantID:  dbRT.TenantID,
+		UserID:    dbRT.UserID,
+		Hash:      dbRT.Hash,
+		ExpiresAt: dbRT.ExpiresAt.In(time.Local),
+		CreatedAt: dbRT.CreatedAt.In(time.Local),
+	}
+	if dbRT.UsedAt.Valid {
+		rt.UsedAt = dbRT.UsedAt.Time.In(time.Local)
+	}
+	if dbRT.RevokedAt.Valid {
+		rt.RevokedAt = dbRT.RevokedAt.Time.In(time.Local)
+	}
+	return rt
+}
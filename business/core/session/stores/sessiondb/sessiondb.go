@@ -0,0 +1,225 @@
+package sessiondb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sales-api/business/core/session"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/transaction"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresRepository manages the set of APIs for session database access.
+// Refresh tokens are found by the hash of their secret value rather than by
+// tenant, since they are presented before anyone is authenticated.
+type PostgresRepository struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+var _ session.Repository = (*PostgresRepository)(nil)
+
+func NewRepository(log *logger.Logger, db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+func (r *PostgresRepository) ExecuteUnderTransaction(tx transaction.Transaction) (session.Repository, error) {
+	ec, err := pgx.GetExtContext(tx)
+
+	if err != nil {
+		return nil, err
+	}
+	r = &PostgresRepository{
+		log: r.log,
+		db:  ec,
+	}
+	return r, nil
+}
+
+// Create inserts a new refresh token into the database.
+func (r *PostgresRepository) Create(ctx context.Context, rt session.RefreshToken) error {
+	const q = `
+	INSERT INTO refresh_tokens
+		(token_id, session_id, tenant_id, user_id, token_hash, expires_at, used_at, revoked_at, created_at)
+	VALUES
+		(:token_id, :session_id, :tenant_id, :user_id, :token_hash, :expires_at, :used_at, :revoked_at, :created_at)`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBRefreshToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// QueryByHash gets the refresh token with the specified hash from the database.
+func (r *PostgresRepository) QueryByHash(ctx context.Context, hash []byte) (session.RefreshToken, error) {
+	data := struct {
+		Hash []byte `db:"token_hash"`
+	}{
+		Hash: hash,
+	}
+
+	const q = `
+	SELECT
+		token_id, session_id, tenant_id, user_id, token_hash, expires_at, used_at, revoked_at, created_at
+	FROM
+		refresh_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var dbRT dbRefreshToken
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dbRT); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return session.RefreshToken{}, fmt.Errorf("namedquerystruct: %w", session.ErrNotFound)
+		}
+		return session.RefreshToken{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreRefreshToken(dbRT), nil
+}
+
+// Exchange marks the refresh token used and inserts the next token of the
+// session. Both happen in one statement, the next token isn't stored unless
+// the token could be marked. It returns ErrReused when the token was already
+// used or revoked.
+func (r *PostgresRepository) Exchange(ctx context.Context, tokenID uuid.UUID, usedAt time.Time, next session.RefreshToken) error {
+	data := struct {
+		dbRefreshToken
+		ExchangedID uuid.UUID `db:"exchanged_id"`
+		ExchangedAt time.Time `db:"exchanged_at"`
+	}{
+		dbRefreshToken: toDBRefreshToken(next),
+		ExchangedID:    tokenID,
+		ExchangedAt:    usedAt.UTC(),
+	}
+
+	const q = `
+	WITH exchanged AS (
+		UPDATE refresh_tokens
+		SET
+			"used_at" = :exchanged_at
+		WHERE
+			token_id = :exchanged_id AND used_at IS NULL AND revoked_at IS NULL
+		RETURNING
+			token_id
+	)
+	INSERT INTO refresh_tokens
+		(token_id, session_id, tenant_id, user_id, token_hash, expires_at, used_at, revoked_at, created_at)
+	SELECT
+		:token_id, :session_id, :tenant_id, :user_id, :token_hash, :expires_at, :used_at, :revoked_at, :created_at
+	FROM
+		exchanged
+	RETURNING
+		token_id`
+
+	var dest struct {
+		ID uuid.UUID `db:"token_id"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dest); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", session.ErrReused)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession revokes every refresh token of the session that isn't
+// already revoked.
+func (r *PostgresRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error {
+	data := struct {
+		SessionID uuid.UUID `db:"session_id"`
+		RevokedAt time.Time `db:"revoked_at"`
+	}{
+		SessionID: sessionID,
+		RevokedAt: revokedAt.UTC(),
+	}
+
+	const q = `
+	UPDATE refresh_tokens
+	SET
+		"revoked_at" = :revoked_at
+	WHERE
+		session_id = :session_id AND revoked_at IS NULL`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccess records the id of an access token that is no longer accepted.
+func (r *PostgresRepository) RevokeAccess(ctx context.Context, jti string, expiresAt time.Time) error {
+	data := struct {
+		JTI       string    `db:"jti"`
+		ExpiresAt time.Time `db:"expires_at"`
+	}{
+		JTI:       jti,
+		ExpiresAt: expiresAt.UTC(),
+	}
+
+	const q = `
+	INSERT INTO revoked_tokens
+		(jti, expires_at)
+	VALUES
+		(:jti, :expires_at)
+	ON CONFLICT (jti) DO NOTHING`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
+
+// IsAccessRevoked reports whether the access token id was revoked, or the
+// session it was issued in was.
+func (r *PostgresRepository) IsAccessRevoked(ctx context.Context, jti string, sessionID uuid.UUID) (bool, error) {
+	data := struct {
+		JTI       string    `db:"jti"`
+		SessionID uuid.UUID `db:"session_id"`
+	}{
+		JTI:       jti,
+		SessionID: sessionID,
+	}
+
+	const q = `
+	SELECT
+		EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = :jti) OR
+		EXISTS (SELECT 1 FROM refresh_tokens WHERE session_id = :session_id AND revoked_at IS NOT NULL) AS revoked`
+
+	var dest struct {
+		Revoked bool `db:"revoked"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dest); err != nil {
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+	return dest.Revoked, nil
+}
+
+// DeleteExpired removes the refresh tokens and access token revocations
+// that have expired.
+func (r *PostgresRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now.UTC(),
+	}
+
+	const q = `
+	WITH expired_refresh AS (
+		DELETE FROM refresh_tokens WHERE expires_at < :now
+	)
+	DELETE FROM revoked_tokens WHERE expires_at < :now`
+
+	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// enabledTTL is how long the enabled state of a user is trusted before it is
+// read again. Changes made by other instances show up after this long.
+const enabledTTL = 10 * time.Second
+
+// EnabledCache holds the enabled state of recently seen users, since it is
+// asked for on every authenticated request.
+type EnabledCache struct {
+	mu      sync.RWMutex
+	entries map[uuid.UUID]enabledState
+	sweptAt time.Time
+}
+
+type enabledState struct {
+	enabled   bool
+	expiresAt time.Time
+}
+
+// NewEnabledCache constructs an empty cache.
+func NewEnabledCache() *EnabledCache {
+	return &EnabledCache{
+		entries: make(map[uuid.UUID]enabledState),
+	}
+}
+
+func (ec *EnabledCache) get(userID uuid.UUID) (bool, bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+
+	state, exists := ec.entries[userID]
+	if !exists || time.Now().After(state.expiresAt) {
+		return false, false
+	}
+	return state.enabled, true
+}
+
+func (ec *EnabledCache) set(userID uuid.UUID, enabled bool) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop what expired so users that stopped calling don't pile up. That
+	// is done once per TTL, so the map holds at most the users seen in the
+	// last two and the sweep is spread over every miss in between.
+	if now.Sub(ec.sweptAt) >= enabledTTL {
+		for id, state := range ec.entries {
+			if now.After(state.expiresAt) {
+				delete(ec.entries, id)
+			}
+		}
+		ec.sweptAt = now
+	}
+
+	ec.entries[userID] = enabledState{
+		enabled:   enabled,
+		expiresAt: now.Add(enabledTTL),
+	}
+}
+
+func (ec *EnabledCache) invalidate(userID uuid.UUID) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	delete(ec.entries, userID)
+}
@@ -13,6 +13,7 @@ type QueryFilter struct {
 	ID               *uuid.UUID    `validate:"omitempty"`
 	Name             *string       `validate:"omitempty,min=3"`
 	Email            *mail.Address `validate:"omitempty"`
+	DepartmentID     *uuid.UUID    `validate:"omitempty"`
 	StartCreatedDate *time.Time    `validate:"omitempty"`
 	EndCreatedDate   *time.Time    `validate:"omitempty"`
 }
@@ -40,6 +41,11 @@ func (qf *QueryFilter) WithEmail(email mail.Address) {
 	qf.Email = &email
 }
 
+// WithDepartmentID sets the DepartmentID field of the QueryFilter value.
+func (qf *QueryFilter) WithDepartmentID(departmentID uuid.UUID) {
+	qf.DepartmentID = &departmentID
+}
+
 // WithStartDateCreated sets the DateCreated field of the QueryFilter value.
 func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
 	d := startDate.UTC()
@@ -10,11 +10,12 @@ import (
 // User represents information about an individual user.
 type User struct {
 	ID           uuid.UUID
+	TenantID     uuid.UUID
 	Name         string
 	Email        mail.Address
 	Roles        []Role
 	PasswordHash []byte
-	Department   string
+	DepartmentID uuid.UUID
 	Enabled      bool
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -22,18 +23,18 @@ type User struct {
 
 // NewUser contains information needed to create a new user.
 type NewUser struct {
-	Name       string
-	Email      mail.Address
-	Roles      []Role
-	Department string
-	Password   string
+	Name         string
+	Email        mail.Address
+	Roles        []Role
+	DepartmentID uuid.UUID
+	Password     string
 }
 
 type UpdateUser struct {
-	Name       *string
-	Email      *mail.Address
-	Roles      []Role
-	Department *string
-	Enabled    *bool
-	Password   *string
+	Name         *string
+	Email        *mail.Address
+	Roles        []Role
+	DepartmentID *uuid.UUID
+	Enabled      *bool
+	Password     *string
 }
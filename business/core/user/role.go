@@ -1,18 +1,25 @@
 package user
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
-// Set of possible roles for a user.
+// Set of built-in roles for a user. They always exist.
 var (
 	RoleAdmin = Role{"ADMIN"}
 	RoleUser  = Role{"USER"}
 )
 
-// Set of known roles.
-var roles = map[string]Role{
-	RoleAdmin.name: RoleAdmin,
-	RoleUser.name:  RoleUser,
-}
+// Set of known roles. The role package replaces it with the roles kept in
+// the database.
+var (
+	rolesMu sync.RWMutex
+	roles   = map[string]Role{
+		RoleAdmin.name: RoleAdmin,
+		RoleUser.name:  RoleUser,
+	}
+)
 
 // Role represents a role in the system.
 type Role struct {
@@ -21,6 +28,9 @@ type Role struct {
 
 // ParseRole parses the string value and returns a role if one exists.
 func ParseRole(value string) (Role, error) {
+	rolesMu.RLock()
+	defer rolesMu.RUnlock()
+
 	role, exists := roles[value]
 	if !exists {
 		return Role{}, fmt.Errorf("invalid role %q", value)
@@ -28,6 +38,22 @@ func ParseRole(value string) (Role, error) {
 	return role, nil
 }
 
+// SetRoles replaces the set of known roles with the specified names. The
+// built-in roles are kept regardless.
+func SetRoles(names []string) {
+	known := map[string]Role{
+		RoleAdmin.name: RoleAdmin,
+		RoleUser.name:  RoleUser,
+	}
+	for _, name := range names {
+		known[name] = Role{name}
+	}
+
+	rolesMu.Lock()
+	defer rolesMu.Unlock()
+	roles = known
+}
+
 // Name returns the name of the role.
 func (r Role) Name() string {
 	return r.name
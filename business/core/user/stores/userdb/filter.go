@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"sales-api/business/core/user"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
-func (r *PostgresRepository) applyFilter(filter user.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
-	var wc []string
+// applyFilter writes the WHERE clause for the filter. Rows are always limited
+// to the tenant, whatever the filter holds.
+func (r *PostgresRepository) applyFilter(tenantID uuid.UUID, filter user.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
+	data["tenant_id"] = tenantID
+	wc := []string{"tenant_id = :tenant_id"}
+
 	if filter.ID != nil {
 		data["user_id"] = *filter.ID
 		wc = append(wc, "user_id = :user_id")
@@ -21,6 +27,10 @@ func (r *PostgresRepository) applyFilter(filter user.QueryFilter, data map[strin
 		data["email"] = (*filter.Email).String()
 		wc = append(wc, "email = :email")
 	}
+	if filter.DepartmentID != nil {
+		data["department_id"] = *filter.DepartmentID
+		wc = append(wc, "department_id = :department_id")
+	}
 
 	if filter.StartCreatedDate != nil {
 		data["start_date_created"] = *filter.StartCreatedDate
@@ -32,8 +42,6 @@ func (r *PostgresRepository) applyFilter(filter user.QueryFilter, data map[strin
 		wc = append(wc, "created_at <= :end_date_created")
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
-	}
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
 }
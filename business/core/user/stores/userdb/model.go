@@ -1,7 +1,6 @@
 package userdb
 
 import (
-	"database/sql"
 	"fmt"
 	"net/mail"
 	"sales-api/business/core/user"
@@ -15,30 +14,32 @@ import (
 // between the app and the database.
 type dbUser struct {
 	ID           uuid.UUID      `db:"user_id"`
+	TenantID     uuid.UUID      `db:"tenant_id"`
 	Name         string         `db:"name"`
 	Email        string         `db:"email"`
 	Roles        dbarray.String `db:"roles"`
 	PasswordHash []byte         `db:"password_hash"`
-	Department   sql.NullString `db:"department"`
+	DepartmentID uuid.NullUUID  `db:"department_id"`
 	Enabled      bool           `db:"enabled"`
 	CreatedAt    time.Time      `db:"created_at"`
 	UpdatedAt    time.Time      `db:"updated_at"`
 }
 
-func toDBUser(usr user.User) dbUser {
+func toDBUser(tenantID uuid.UUID, usr user.User) dbUser {
 	roles := make([]string, len(usr.Roles))
 	for i, role := range usr.Roles {
 		roles[i] = role.Name()
 	}
 	return dbUser{
 		ID:           usr.ID,
+		TenantID:     tenantID,
 		Name:         usr.Name,
 		Email:        usr.Email.Address,
 		Roles:        roles,
 		PasswordHash: usr.PasswordHash,
-		Department: sql.NullString{
-			String: usr.Department,
-			Valid:  usr.Department != "",
+		DepartmentID: uuid.NullUUID{
+			UUID:  usr.DepartmentID,
+			Valid: usr.DepartmentID != uuid.Nil,
 		},
 		Enabled:   usr.Enabled,
 		CreatedAt: usr.CreatedAt.UTC(),
@@ -63,12 +64,13 @@ func toCoreUser(dbUsr dbUser) (user.User, error) {
 
 	usr := user.User{
 		ID:           dbUsr.ID,
+		TenantID:     dbUsr.TenantID,
 		Name:         dbUsr.Name,
 		Email:        addr,
 		Roles:        roles,
 		PasswordHash: dbUsr.PasswordHash,
 		Enabled:      dbUsr.Enabled,
-		Department:   dbUsr.Department.String,
+		DepartmentID: dbUsr.DepartmentID.UUID,
 		CreatedAt:    dbUsr.CreatedAt.In(time.Local),
 		UpdatedAt:    dbUsr.UpdatedAt.In(time.Local),
 	}
@@ -9,6 +9,7 @@ import (
 	"sales-api/business/core/user"
 	"sales-api/business/data/dbsql/pgx"
 	"sales-api/business/data/order"
+	"sales-api/business/data/tenant"
 	"sales-api/business/data/transaction"
 	"sales-api/foundation/logger"
 
@@ -44,16 +45,24 @@ func (r *PostgresRepository) ExecuteUnderTransaction(tx transaction.Transaction)
 }
 
 func (s *PostgresRepository) Create(ctx context.Context, usr user.User) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
 	const q = `
 	INSERT INTO users
-		(user_id, name, email, password_hash, roles, enabled, department, created_at, updated_at)
+		(user_id, tenant_id, name, email, password_hash, roles, enabled, department_id, created_at, updated_at)
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :enabled, :department, :created_at, :updated_at)`
+		(:user_id, :tenant_id, :name, :email, :password_hash, :roles, :enabled, :department_id, :created_at, :updated_at)`
 
-	if err := pgx.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+	if err := pgx.NamedExecContext(ctx, s.log, s.db, q, toDBUser(tenantID, usr)); err != nil {
 		if errors.Is(err, pgx.ErrDBDuplicatedEntry) {
 			return fmt.Errorf("namedexeccontext: %w", user.ErrUniqueEmail)
 		}
+		if errors.Is(err, pgx.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", user.ErrDepartmentNotFound)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 
 	}
@@ -61,22 +70,58 @@ func (s *PostgresRepository) Create(ctx context.Context, usr user.User) error {
 }
 
 func (r *PostgresRepository) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return user.User{}, err
+	}
+
 	data := struct {
-		ID uuid.UUID `db:"user_id"`
+		ID       uuid.UUID `db:"user_id"`
+		TenantID uuid.UUID `db:"tenant_id"`
 	}{
-		ID: userID,
+		ID:       userID,
+		TenantID: tenantID,
 	}
 	const q = `
 		SELECT
-        user_id, name, email, password_hash, roles, enabled, department, created_at, updated_at
+        user_id, tenant_id, name, email, password_hash, roles, enabled, department_id, created_at, updated_at
 	FROM
 		users
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND tenant_id = :tenant_id`
 	return r.queryUser(ctx, q, data)
 }
 
 func (r *PostgresRepository) QueryByEmail(ctx context.Context, email mail.Address) (user.User, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	data := struct {
+		Email    string    `db:"email"`
+		TenantID uuid.UUID `db:"tenant_id"`
+	}{
+		Email:    email.Address,
+		TenantID: tenantID,
+	}
+
+	const q = `
+		SELECT
+        user_id, tenant_id, name, email, password_hash, roles, enabled, department_id, created_at, updated_at
+	FROM
+		users
+	WHERE
+		email = :email AND tenant_id = :tenant_id`
+
+	return r.queryUser(ctx, q, data)
+
+}
+
+// QueryByEmailAcrossTenants gets the user with the specified email whatever
+// organization they belong to. Emails are unique across organizations, and
+// this is how a login finds out which organization the user belongs to.
+func (r *PostgresRepository) QueryByEmailAcrossTenants(ctx context.Context, email mail.Address) (user.User, error) {
 	data := struct {
 		Email string `db:"email"`
 	}{
@@ -85,7 +130,7 @@ func (r *PostgresRepository) QueryByEmail(ctx context.Context, email mail.Addres
 
 	const q = `
 		SELECT
-        user_id, name, email, password_hash, roles, enabled, department, created_at, updated_at
+        user_id, tenant_id, name, email, password_hash, roles, enabled, department_id, created_at, updated_at
 	FROM
 		users
 	WHERE
@@ -97,6 +142,11 @@ func (r *PostgresRepository) QueryByEmail(ctx context.Context, email mail.Addres
 
 // Query retrieves a list of existing users from the database.
 func (r *PostgresRepository) Query(ctx context.Context, filter user.QueryFilter, orderBy order.By, page int, pageSize int) ([]user.User, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	data := map[string]any{
 		"offset": (page - 1) * pageSize,
 		"limit":  pageSize,
@@ -104,12 +154,12 @@ func (r *PostgresRepository) Query(ctx context.Context, filter user.QueryFilter,
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, enabled, department, created_at, updated_at
+        user_id, tenant_id, name, email, password_hash, roles, enabled, department_id, created_at, updated_at
 	FROM
 		users`
 
 	buf := bytes.NewBufferString(q)
-	r.applyFilter(filter, data, buf)
+	r.applyFilter(tenantID, filter, data, buf)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -132,6 +182,11 @@ func (r *PostgresRepository) Query(ctx context.Context, filter user.QueryFilter,
 
 // Update replaces a user document in the database.
 func (r *PostgresRepository) Update(ctx context.Context, usr user.User) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+
 	const q = `
 	UPDATE users
 	SET
@@ -139,43 +194,71 @@ func (r *PostgresRepository) Update(ctx context.Context, usr user.User) error {
 		"email" = :email,
 		"roles" = :roles,
 		"password_hash" = :password_hash,
-		"department" = :department,
+		"department_id" = :department_id,
+		"enabled" = :enabled,
 		"updated_at" = :updated_at
 	WHERE
-	 	user_id = :user_id`
+	 	user_id = :user_id AND tenant_id = :tenant_id
+	RETURNING
+		user_id`
 
-	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, toDBUser(usr)); err != nil {
+	var dest struct {
+		ID uuid.UUID `db:"user_id"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, toDBUser(tenantID, usr), &dest); err != nil {
+		if errors.Is(err, pgx.ErrDBNotFound) {
+			return user.ErrNotFound
+		}
 		if errors.Is(err, pgx.ErrDBDuplicatedEntry) {
 			return user.ErrUniqueEmail
 		}
-		return fmt.Errorf("namedexeccontext: %w", err)
+		if errors.Is(err, pgx.ErrDBForeignKey) {
+			return user.ErrDepartmentNotFound
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 	return nil
 }
 
 // Update replaces a user document in the database.
 func (r *PostgresRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
 
 	data := struct {
-		UserId string `db:"user_id"`
+		UserId   string `db:"user_id"`
+		TenantID string `db:"tenant_id"`
 	}{
-		UserId: userID.String(),
+		UserId:   userID.String(),
+		TenantID: tenantID.String(),
 	}
 	const q = `
 	DELETE FROM users
 	WHERE
-	 	user_id = :user_id`
+	 	user_id = :user_id AND tenant_id = :tenant_id
+	RETURNING
+		user_id`
 
-	if err := pgx.NamedExecContext(ctx, r.log, r.db, q, data); err != nil {
+	var dest struct {
+		ID uuid.UUID `db:"user_id"`
+	}
+	if err := pgx.NamedQueryStruct(ctx, r.log, r.db, q, data, &dest); err != nil {
 		if errors.Is(err, pgx.ErrDBNotFound) {
 			return user.ErrNotFound
 		}
-		return fmt.Errorf("namedexeccontext: %w", err)
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 	return nil
 }
 
 func (r *PostgresRepository) Count(ctx context.Context, filter user.QueryFilter) (int, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	data := map[string]any{}
 
 	const q = `
@@ -185,7 +268,7 @@ func (r *PostgresRepository) Count(ctx context.Context, filter user.QueryFilter)
 		users`
 
 	buf := bytes.NewBufferString(q)
-	r.applyFilter(filter, data, buf)
+	r.applyFilter(tenantID, filter, data, buf)
 
 	var count struct {
 		Count int `db:"count"`
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/mail"
 	"sales-api/business/data/order"
+	"sales-api/business/data/tenant"
 	"sales-api/business/data/transaction"
 	"sales-api/foundation/logger"
 	"time"
@@ -19,7 +20,9 @@ import (
 var (
 	ErrNotFound              = errors.New("user not found")
 	ErrUniqueEmail           = errors.New("email is not unique")
+	ErrDepartmentNotFound    = errors.New("department not found")
 	ErrAuthenticationFailure = errors.New("authentication failed")
+	ErrDisabled              = errors.New("user disabled")
 )
 
 // Repository interface declares the behavior this package needs to perists and
@@ -34,6 +37,7 @@ type Repository interface {
 	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	// QueryByIDs(ctx context.Context, userID []uuid.UUID) ([]User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
+	QueryByEmailAcrossTenants(ctx context.Context, email mail.Address) (User, error)
 }
 
 // =============================================================================
@@ -41,13 +45,17 @@ type Repository interface {
 // Core manages the set of APIs for user access.
 type Core struct {
 	repository Repository
+	enabled    *EnabledCache
 	log        *logger.Logger
 }
 
-// NewCore constructs a core for user api access.
-func NewCore(log *logger.Logger, repository Repository) *Core {
+// NewCore constructs a core for user api access. Every core of the service
+// should share the same cache, so a user disabled through one is refused by
+// all of them.
+func NewCore(log *logger.Logger, enabled *EnabledCache, repository Repository) *Core {
 	return &Core{
 		repository: repository,
+		enabled:    enabled,
 		log:        log,
 	}
 }
@@ -61,6 +69,7 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 	}
 	c = &Core{
 		repository: trs,
+		enabled:    c.enabled,
 		log:        c.log,
 	}
 
@@ -68,6 +77,11 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 }
 
 func (c *Core) Create(ctx context.Context, nu NewUser) (User, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return User{}, err
+	}
+
 	hash, err := bcrypt.GenerateFromPassword([]byte(nu.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return User{}, fmt.Errorf("generatefrompassword: %w", err)
@@ -77,11 +91,12 @@ func (c *Core) Create(ctx context.Context, nu NewUser) (User, error) {
 
 	usr := User{
 		ID:           uuid.New(),
+		TenantID:     tenantID,
 		Name:         nu.Name,
 		Email:        nu.Email,
 		Roles:        nu.Roles,
 		PasswordHash: hash,
-		Department:   nu.Department,
+		DepartmentID: nu.DepartmentID,
 		Enabled:      true,
 		CreatedAt:    now,
 		UpdatedAt:    now,
@@ -147,8 +162,8 @@ func (c *Core) Update(ctx context.Context, usr User, uu UpdateUser) (User, error
 		usr.PasswordHash = pw
 	}
 
-	if uu.Department != nil {
-		usr.Department = *uu.Department
+	if uu.DepartmentID != nil {
+		usr.DepartmentID = *uu.DepartmentID
 	}
 
 	if uu.Enabled != nil {
@@ -161,6 +176,12 @@ func (c *Core) Update(ctx context.Context, usr User, uu UpdateUser) (User, error
 		return User{}, fmt.Errorf("update: %w", err)
 	}
 
+	// Tokens of a user that was just disabled must stop working now, not
+	// when the cached answer expires.
+	if uu.Enabled != nil {
+		c.invalidate(ctx, usr.ID)
+	}
+
 	return usr, nil
 
 }
@@ -172,22 +193,57 @@ func (c *Core) Delete(ctx context.Context, userID uuid.UUID) error {
 	if err := c.repository.Delete(ctx, userID); err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
+
+	c.invalidate(ctx, userID)
+
 	return nil
 }
 
+// IsEnabled reports whether the user is enabled. Answers are cached for a
+// short while since this is asked on every authenticated request.
+func (c *Core) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if enabled, exists := c.enabled.get(userID); exists {
+		return enabled, nil
+	}
+
+	usr, err := c.repository.QueryByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("query: user_id[%s]: %w", userID, err)
+	}
+
+	c.enabled.set(userID, usr.Enabled)
+
+	return usr.Enabled, nil
+}
+
+// invalidate drops the cached enabled state of the user. Until the change
+// commits other requests still read the old state and may cache it again,
+// so it is dropped once more after the commit.
+func (c *Core) invalidate(ctx context.Context, userID uuid.UUID) {
+	c.enabled.invalidate(userID)
+	transaction.AfterCommit(ctx, func() {
+		c.enabled.invalidate(userID)
+	})
+}
+
 // ============================================================================
 
 // Authenticate finds a user by their email and verifies their password. On
 // success it returns a Claims User representing this user. The claims can be
-// used to generate a token for future authentication.
+// used to generate a token for future authentication. The user is looked up
+// in every organization since the caller doesn't know theirs yet. A disabled
+// user can't log in.
 
 func (c *Core) Authenticate(ctx context.Context, email mail.Address, pass string) (User, error) {
-	usr, err := c.QueryByEmail(ctx, email)
+	usr, err := c.repository.QueryByEmailAcrossTenants(ctx, email)
 	if err != nil {
 		return User{}, fmt.Errorf("query: email[%s]: %w", email, err)
 	}
 	if err := bcrypt.CompareHashAndPassword(usr.PasswordHash, []byte(pass)); err != nil {
 		return User{}, fmt.Errorf("comparehashandpassword: %w", ErrAuthenticationFailure)
 	}
+	if !usr.Enabled {
+		return User{}, ErrDisabled
+	}
 	return usr, nil
 }
@@ -3,8 +3,12 @@ package user_test
 import (
 	"context"
 	"net/mail"
+	"sales-api/business/core/organization"
 	"sales-api/business/core/user"
+	"sales-api/business/data/dbsql/pgx"
+	"sales-api/business/data/tenant"
 	"sales-api/business/data/test"
+	"sales-api/business/data/transaction"
 	"testing"
 
 	"github.com/google/uuid"
@@ -34,12 +38,11 @@ func (suite *UserTestSuite) TestCreate() {
 		Roles: []user.Role{
 			user.RoleUser,
 		},
-		Department: "Technology",
-		Password:   "password",
+		Password: "password",
 	}
 	suite.createUser(nu)
 	// Test duplicate entry
-	_, err = suite.test.CoreAPIs.User.Create(context.Background(), nu)
+	_, err = suite.test.CoreAPIs.User.Create(suite.test.Context(), nu)
 	suite.Error(err)
 	suite.ErrorIs(err, user.ErrUniqueEmail)
 }
@@ -53,31 +56,187 @@ func (suite *UserTestSuite) TestQueryByID() {
 		Roles: []user.Role{
 			user.RoleUser,
 		},
-		Department: "Technology",
-		Password:   "password",
+		Password: "password",
 	}
 	// Create new user
 	usr := suite.createUser(nu)
 	// Test query by id
-	qusr, err := suite.test.CoreAPIs.User.QueryByID(context.Background(), usr.ID)
+	qusr, err := suite.test.CoreAPIs.User.QueryByID(suite.test.Context(), usr.ID)
 	suite.NoError(err)
 	suite.Equal(usr.Email, qusr.Email)
 	suite.Equal(usr.Name, qusr.Name)
 	suite.Equal(usr.ID.String(), qusr.ID.String())
 
 	// Test query by id not found
-	_, err = suite.test.CoreAPIs.User.QueryByID(context.Background(), uuid.New())
+	_, err = suite.test.CoreAPIs.User.QueryByID(suite.test.Context(), uuid.New())
 	suite.Error(err)
 	suite.ErrorIs(err, user.ErrNotFound)
 
 }
 
+func (suite *UserTestSuite) TestDisable() {
+	ctx := suite.test.Context()
+
+	email, err := mail.ParseAddress("disabled@gmail.com")
+	suite.NoError(err)
+	usr := suite.createUser(user.NewUser{
+		Name:     "Disabled",
+		Email:    *email,
+		Roles:    []user.Role{user.RoleUser},
+		Password: "password",
+	})
+
+	enabled, err := suite.test.CoreAPIs.User.IsEnabled(ctx, usr.ID)
+	suite.NoError(err)
+	suite.True(enabled)
+
+	// The cached answer is dropped as soon as the user is disabled.
+	disabled := false
+	_, err = suite.test.CoreAPIs.User.Update(ctx, usr, user.UpdateUser{Enabled: &disabled})
+	suite.NoError(err)
+
+	enabled, err = suite.test.CoreAPIs.User.IsEnabled(ctx, usr.ID)
+	suite.NoError(err)
+	suite.False(enabled)
+
+	// A disabled user can't log in.
+	_, err = suite.test.CoreAPIs.User.Authenticate(ctx, *email, "password")
+	suite.ErrorIs(err, user.ErrDisabled)
+}
+
+func (suite *UserTestSuite) TestDisableInTransaction() {
+	ctx := suite.test.Context()
+
+	email, err := mail.ParseAddress("disabled-tx@gmail.com")
+	suite.NoError(err)
+	usr := suite.createUser(user.NewUser{
+		Name:     "Disabled Tx",
+		Email:    *email,
+		Roles:    []user.Role{user.RoleUser},
+		Password: "password",
+	})
+
+	tx, err := pgx.NewBeginner(suite.test.DB).Begin(ctx)
+	suite.NoError(err)
+	defer tx.Rollback()
+	txCtx := transaction.Set(ctx, tx)
+
+	core, err := suite.test.CoreAPIs.User.ExecuteUnderTransaction(tx)
+	suite.NoError(err)
+
+	disabled := false
+	_, err = core.Update(txCtx, usr, user.UpdateUser{Enabled: &disabled})
+	suite.NoError(err)
+
+	// Until the change commits another request still reads the user as
+	// enabled, and caches that.
+	enabled, err := suite.test.CoreAPIs.User.IsEnabled(ctx, usr.ID)
+	suite.NoError(err)
+	suite.True(enabled)
+
+	suite.NoError(tx.Commit())
+	transaction.Committed(txCtx)
+
+	enabled, err = suite.test.CoreAPIs.User.IsEnabled(ctx, usr.ID)
+	suite.NoError(err)
+	suite.False(enabled)
+}
+
+func (suite *UserTestSuite) TestTenantIsolation() {
+	org, err := suite.test.CoreAPIs.Organization.Create(context.Background(), organization.NewOrganization{Name: "Other Co"})
+	suite.NoError(err)
+	otherCtx := tenant.Set(context.Background(), org.ID)
+
+	email, err := mail.ParseAddress("other@gmail.com")
+	suite.NoError(err)
+	usr, err := suite.test.CoreAPIs.User.Create(otherCtx, user.NewUser{
+		Name:     "Other",
+		Email:    *email,
+		Roles:    []user.Role{user.RoleUser},
+		Password: "password",
+	})
+	suite.NoError(err)
+	suite.Equal(org.ID, usr.TenantID)
+
+	// The user can't be read, changed or counted from another organization.
+	_, err = suite.test.CoreAPIs.User.QueryByID(suite.test.Context(), usr.ID)
+	suite.ErrorIs(err, user.ErrNotFound)
+
+	var filter user.QueryFilter
+	filter.WithEmail(*email)
+	count, err := suite.test.CoreAPIs.User.Count(suite.test.Context(), filter)
+	suite.NoError(err)
+	suite.Zero(count)
+
+	name := "Renamed"
+	_, err = suite.test.CoreAPIs.User.Update(suite.test.Context(), usr, user.UpdateUser{Name: &name})
+	suite.ErrorIs(err, user.ErrNotFound)
+
+	err = suite.test.CoreAPIs.User.Delete(suite.test.Context(), usr.ID)
+	suite.ErrorIs(err, user.ErrNotFound)
+
+	usr, err = suite.test.CoreAPIs.User.QueryByID(otherCtx, usr.ID)
+	suite.NoError(err)
+	suite.Equal("Other", usr.Name)
+
+	// Stores refuse to work without a tenant.
+	_, err = suite.test.CoreAPIs.User.QueryByID(context.Background(), usr.ID)
+	suite.ErrorIs(err, tenant.ErrMissing)
+}
+
+// TestRowLevelSecurity runs a statement that doesn't filter by tenant, the
+// database does it instead.
+func (suite *UserTestSuite) TestRowLevelSecurity() {
+	org, err := suite.test.CoreAPIs.Organization.Create(context.Background(), organization.NewOrganization{Name: "Hidden Co"})
+	suite.NoError(err)
+	otherCtx := tenant.Set(context.Background(), org.ID)
+
+	email, err := mail.ParseAddress("hidden@gmail.com")
+	suite.NoError(err)
+	_, err = suite.test.CoreAPIs.User.Create(otherCtx, user.NewUser{
+		Name:     "Hidden",
+		Email:    *email,
+		Roles:    []user.Role{user.RoleUser},
+		Password: "password",
+	})
+	suite.NoError(err)
+
+	type row struct {
+		TenantID uuid.UUID `db:"tenant_id"`
+	}
+	const q = `SELECT tenant_id FROM users`
+
+	var rows []row
+	err = pgx.NamedQuerySlice(suite.test.Context(), suite.test.Log, suite.test.DB, q, struct{}{}, &rows)
+	suite.NoError(err)
+	suite.NotEmpty(rows)
+	for _, r := range rows {
+		suite.Equal(test.DefaultTenantID, r.TenantID)
+	}
+
+	// Without a tenant nothing is visible.
+	err = pgx.NamedQuerySlice(context.Background(), suite.test.Log, suite.test.DB, q, struct{}{}, &rows)
+	suite.NoError(err)
+	suite.Empty(rows)
+
+	// The maintenance role sees every tenant.
+	err = pgx.NamedQuerySlice(context.Background(), suite.test.Log, suite.test.MaintenanceDB, q, struct{}{}, &rows)
+	suite.NoError(err)
+
+	tenants := make(map[uuid.UUID]bool)
+	for _, r := range rows {
+		tenants[r.TenantID] = true
+	}
+	suite.True(tenants[test.DefaultTenantID])
+	suite.True(tenants[org.ID])
+}
+
 func (suite *UserTestSuite) createUser(nu user.NewUser) user.User {
-	usr, err := suite.test.CoreAPIs.User.Create(context.Background(), nu)
+	usr, err := suite.test.CoreAPIs.User.Create(suite.test.Context(), nu)
 	suite.NoError(err)
 	suite.NotEmpty(usr)
 	suite.Equal(nu.Name, usr.Name)
-	suite.Equal(nu.Department, usr.Department)
+	suite.Equal(nu.DepartmentID, usr.DepartmentID)
 	return usr
 }
 
@@ -0,0 +1,44 @@
+// Package actor provides support for carrying the user a request acts as
+// through the context, so the data layer can hand it to the database.
+package actor
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ctxKey int
+
+const actorKey ctxKey = 1
+
+// Set stores the id of the acting user in the context.
+func Set(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey, userID)
+}
+
+// Get retrieves the id of the acting user from the context.
+func Get(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(actorKey).(uuid.UUID)
+	if !ok || v == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return v, true
+}
+
+const targetKey ctxKey = 2
+
+// SetTarget stores the id of the user a request acts on, once the acting
+// user has been authorized for them.
+func SetTarget(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, targetKey, userID)
+}
+
+// GetTarget retrieves the id of the user a request acts on from the context.
+func GetTarget(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(targetKey).(uuid.UUID)
+	if !ok || v == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return v, true
+}
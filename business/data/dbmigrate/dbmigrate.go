@@ -11,6 +11,13 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// Roles the service connects as. The migrations create them without a
+// password, whoever runs the migrations sets one.
+const (
+	AppRole         = "sales_app"
+	MaintenanceRole = "sales_maintenance"
+)
+
 func Migration(ctx context.Context, source string, db *sqlx.DB) error {
 	if err := pgx.StatusCheck(ctx, db); err != nil {
 		return fmt.Errorf("status check database: %w", err)
@@ -28,3 +35,21 @@ func Migration(ctx context.Context, source string, db *sqlx.DB) error {
 	}
 	return err
 }
+
+// SetPassword sets the password a role logs in with.
+func SetPassword(ctx context.Context, db *sqlx.DB, role string, password string) error {
+
+	// ALTER ROLE doesn't take parameters, the server quotes the values.
+	const q = `SELECT format('ALTER ROLE %I PASSWORD %L', $1::TEXT, $2::TEXT)`
+
+	var stmt string
+	if err := db.QueryRowContext(ctx, q, role, password).Scan(&stmt); err != nil {
+		return fmt.Errorf("quoting role[%s]: %w", role, err)
+	}
+
+	if _, err := db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("setting password role[%s]: %w", role, err)
+	}
+
+	return nil
+}
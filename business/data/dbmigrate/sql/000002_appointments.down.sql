@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS appointment_feeds;
+DROP TABLE IF EXISTS appointments;
@@ -0,0 +1,31 @@
+-- Description: Create tables appointments and appointment_feeds
+
+CREATE TABLE appointments (
+	appointment_id  UUID        NOT NULL,
+	owner_id        UUID        NOT NULL,
+	title           TEXT        NOT NULL,
+	description     TEXT        NULL,
+	location        TEXT        NULL,
+	attendees       TEXT[]      NOT NULL,
+	time_zone       TEXT        NOT NULL,
+	starts_at       TIMESTAMP   NOT NULL,
+	ends_at         TIMESTAMP   NOT NULL,
+	recurrence      TEXT        NULL,
+	series_ends_at  TIMESTAMP   NULL,
+	created_at      TIMESTAMP   NOT NULL DEFAULT NOW(),
+	updated_at      TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY (appointment_id),
+	FOREIGN KEY (owner_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX appointments_owner_starts_at_idx ON appointments (owner_id, starts_at);
+
+CREATE TABLE appointment_feeds (
+	owner_id    UUID        NOT NULL,
+	token_hash  TEXT        NOT NULL UNIQUE,
+	created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY (owner_id),
+	FOREIGN KEY (owner_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN department TEXT NULL;
+
+UPDATE users SET department = d.name
+	FROM departments AS d
+	WHERE users.department_id = d.department_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS department_id;
+DROP TABLE IF EXISTS departments;
@@ -0,0 +1,27 @@
+-- Description: Create table departments and link users to them by ID
+
+CREATE TABLE departments (
+	department_id  UUID        NOT NULL,
+	name           TEXT        UNIQUE NOT NULL,
+	manager_id     UUID        NULL,
+	parent_id      UUID        NULL,
+	created_at     TIMESTAMP   NOT NULL DEFAULT NOW(),
+	updated_at     TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY (department_id),
+	FOREIGN KEY (manager_id) REFERENCES users(user_id) ON DELETE SET NULL,
+	FOREIGN KEY (parent_id) REFERENCES departments(department_id) ON DELETE SET NULL
+);
+
+ALTER TABLE users ADD COLUMN department_id UUID NULL REFERENCES departments(department_id) ON DELETE SET NULL;
+
+-- Turn the free text departments users already have into real ones.
+INSERT INTO departments (department_id, name)
+	SELECT gen_random_uuid(), department
+	FROM (SELECT DISTINCT department FROM users WHERE department IS NOT NULL AND department <> '') AS d;
+
+UPDATE users SET department_id = d.department_id
+	FROM departments AS d
+	WHERE users.department = d.name;
+
+ALTER TABLE users DROP COLUMN department;
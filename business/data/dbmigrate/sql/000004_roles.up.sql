@@ -0,0 +1,18 @@
+-- Description: Create table roles
+
+CREATE TABLE roles (
+	name        TEXT      NOT NULL,
+	description TEXT      NOT NULL DEFAULT '',
+	permissions TEXT[]    NOT NULL,
+	built_in    BOOLEAN   NOT NULL DEFAULT FALSE,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY (name)
+);
+
+-- SEED ROLES
+INSERT INTO roles (name, description, permissions, built_in) VALUES
+	('ADMIN', 'Full access to every user and setting', '{admin}', TRUE),
+	('USER', 'Access to their own data and that of the users reporting to them', '{self,reports}', TRUE)
+ON CONFLICT DO NOTHING;
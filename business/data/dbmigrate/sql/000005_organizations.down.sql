@@ -0,0 +1,35 @@
+-- Only the first organization survives going back to a single tenant.
+DELETE FROM organizations WHERE organization_id <> '9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05';
+
+DROP INDEX IF EXISTS roles_built_in_name_idx;
+ALTER TABLE roles DROP CONSTRAINT roles_tenant_name_key;
+ALTER TABLE roles DROP COLUMN tenant_id;
+ALTER TABLE roles ADD PRIMARY KEY (name);
+
+ALTER TABLE appointment_feeds DROP CONSTRAINT appointment_feeds_owner_id_fkey;
+ALTER TABLE appointments DROP CONSTRAINT appointments_owner_id_fkey;
+ALTER TABLE users DROP CONSTRAINT users_department_id_fkey;
+ALTER TABLE departments DROP CONSTRAINT departments_parent_id_fkey;
+ALTER TABLE departments DROP CONSTRAINT departments_manager_id_fkey;
+ALTER TABLE departments DROP CONSTRAINT departments_tenant_name_key;
+ALTER TABLE departments DROP CONSTRAINT departments_tenant_department_key;
+ALTER TABLE users DROP CONSTRAINT users_tenant_user_key;
+
+ALTER TABLE appointment_feeds DROP COLUMN tenant_id;
+ALTER TABLE appointments DROP COLUMN tenant_id;
+ALTER TABLE departments DROP COLUMN tenant_id;
+ALTER TABLE users DROP COLUMN tenant_id;
+
+ALTER TABLE departments ADD CONSTRAINT departments_name_key UNIQUE (name);
+ALTER TABLE departments ADD CONSTRAINT departments_manager_id_fkey
+	FOREIGN KEY (manager_id) REFERENCES users(user_id) ON DELETE SET NULL;
+ALTER TABLE departments ADD CONSTRAINT departments_parent_id_fkey
+	FOREIGN KEY (parent_id) REFERENCES departments(department_id) ON DELETE SET NULL;
+ALTER TABLE users ADD CONSTRAINT users_department_id_fkey
+	FOREIGN KEY (department_id) REFERENCES departments(department_id) ON DELETE SET NULL;
+ALTER TABLE appointments ADD CONSTRAINT appointments_owner_id_fkey
+	FOREIGN KEY (owner_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE appointment_feeds ADD CONSTRAINT appointment_feeds_owner_id_fkey
+	FOREIGN KEY (owner_id) REFERENCES users(user_id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,78 @@
+-- Description: Create table organizations and scope every domain table to one
+
+CREATE TABLE organizations (
+	organization_id UUID      NOT NULL,
+	name            TEXT      NOT NULL,
+	created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY (organization_id)
+);
+
+-- Everything that exists so far belongs to the first organization.
+INSERT INTO organizations (organization_id, name) VALUES
+	('9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05', 'Default Organization')
+ON CONFLICT DO NOTHING;
+
+-- ==============================================================================
+-- users
+
+ALTER TABLE users ADD COLUMN tenant_id UUID NULL REFERENCES organizations(organization_id) ON DELETE CASCADE;
+UPDATE users SET tenant_id = '9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05';
+ALTER TABLE users ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_tenant_user_key UNIQUE (tenant_id, user_id);
+
+-- ==============================================================================
+-- departments
+
+ALTER TABLE departments ADD COLUMN tenant_id UUID NULL REFERENCES organizations(organization_id) ON DELETE CASCADE;
+UPDATE departments SET tenant_id = '9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05';
+ALTER TABLE departments ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE departments ADD CONSTRAINT departments_tenant_department_key UNIQUE (tenant_id, department_id);
+
+ALTER TABLE departments DROP CONSTRAINT departments_name_key;
+ALTER TABLE departments ADD CONSTRAINT departments_tenant_name_key UNIQUE (tenant_id, name);
+
+-- Links between rows must stay inside one organization.
+ALTER TABLE departments DROP CONSTRAINT departments_manager_id_fkey;
+ALTER TABLE departments ADD CONSTRAINT departments_manager_id_fkey
+	FOREIGN KEY (tenant_id, manager_id) REFERENCES users(tenant_id, user_id) ON DELETE SET NULL (manager_id);
+
+ALTER TABLE departments DROP CONSTRAINT departments_parent_id_fkey;
+ALTER TABLE departments ADD CONSTRAINT departments_parent_id_fkey
+	FOREIGN KEY (tenant_id, parent_id) REFERENCES departments(tenant_id, department_id) ON DELETE SET NULL (parent_id);
+
+ALTER TABLE users DROP CONSTRAINT users_department_id_fkey;
+ALTER TABLE users ADD CONSTRAINT users_department_id_fkey
+	FOREIGN KEY (tenant_id, department_id) REFERENCES departments(tenant_id, department_id) ON DELETE SET NULL (department_id);
+
+-- ==============================================================================
+-- appointments
+
+ALTER TABLE appointments ADD COLUMN tenant_id UUID NULL;
+UPDATE appointments SET tenant_id = '9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05';
+ALTER TABLE appointments ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE appointments DROP CONSTRAINT appointments_owner_id_fkey;
+ALTER TABLE appointments ADD CONSTRAINT appointments_owner_id_fkey
+	FOREIGN KEY (tenant_id, owner_id) REFERENCES users(tenant_id, user_id) ON DELETE CASCADE;
+
+ALTER TABLE appointment_feeds ADD COLUMN tenant_id UUID NULL;
+UPDATE appointment_feeds SET tenant_id = '9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05';
+ALTER TABLE appointment_feeds ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE appointment_feeds DROP CONSTRAINT appointment_feeds_owner_id_fkey;
+ALTER TABLE appointment_feeds ADD CONSTRAINT appointment_feeds_owner_id_fkey
+	FOREIGN KEY (tenant_id, owner_id) REFERENCES users(tenant_id, user_id) ON DELETE CASCADE;
+
+-- ==============================================================================
+-- roles
+
+-- Built-in roles are shared by every organization and keep a NULL tenant.
+-- Custom roles belong to the organization that created them.
+ALTER TABLE roles ADD COLUMN tenant_id UUID NULL REFERENCES organizations(organization_id) ON DELETE CASCADE;
+UPDATE roles SET tenant_id = '9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05' WHERE NOT built_in;
+
+ALTER TABLE roles DROP CONSTRAINT roles_pkey;
+ALTER TABLE roles ADD CONSTRAINT roles_tenant_name_key UNIQUE (tenant_id, name);
+CREATE UNIQUE INDEX roles_built_in_name_idx ON roles (name) WHERE tenant_id IS NULL;
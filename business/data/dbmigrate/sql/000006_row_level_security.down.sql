@@ -0,0 +1,25 @@
+DROP POLICY IF EXISTS roles_tenant ON roles;
+ALTER TABLE roles NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE roles DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS appointment_feeds_tenant ON appointment_feeds;
+ALTER TABLE appointment_feeds NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE appointment_feeds DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS appointments_tenant ON appointments;
+ALTER TABLE appointments NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE appointments DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS departments_tenant ON departments;
+ALTER TABLE departments NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE departments DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_tenant ON users;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS organizations_tenant ON organizations;
+ALTER TABLE organizations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE organizations DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_tenant_visible(UUID);
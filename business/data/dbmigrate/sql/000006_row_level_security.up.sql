@@ -0,0 +1,70 @@
+-- Description: Enforce tenancy in the database with row level security
+
+-- Request transactions set app.tenant_id and app.user_id from the caller's
+-- claims. A row is visible when it belongs to that tenant. Work that runs
+-- outside of a request transaction (login, migrations, seeding, loading the
+-- role registry at startup) leaves the setting empty and is not filtered.
+--
+-- Superusers and roles with BYPASSRLS skip these policies altogether, so the
+-- service must connect as an ordinary role for them to take effect.
+CREATE FUNCTION app_tenant_visible(row_tenant UUID) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+	SELECT NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+		OR row_tenant = NULLIF(current_setting('app.tenant_id', true), '')::UUID
+$$;
+
+-- ==============================================================================
+-- organizations
+
+ALTER TABLE organizations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organizations FORCE ROW LEVEL SECURITY;
+
+-- Creating an organization is not tied to the caller's own tenant.
+CREATE POLICY organizations_tenant ON organizations
+	USING (app_tenant_visible(organization_id))
+	WITH CHECK (true);
+
+-- ==============================================================================
+-- users
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY users_tenant ON users
+	USING (app_tenant_visible(tenant_id));
+
+-- ==============================================================================
+-- departments
+
+ALTER TABLE departments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE departments FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY departments_tenant ON departments
+	USING (app_tenant_visible(tenant_id));
+
+-- ==============================================================================
+-- appointments
+
+ALTER TABLE appointments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE appointments FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY appointments_tenant ON appointments
+	USING (app_tenant_visible(tenant_id));
+
+ALTER TABLE appointment_feeds ENABLE ROW LEVEL SECURITY;
+ALTER TABLE appointment_feeds FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY appointment_feeds_tenant ON appointment_feeds
+	USING (app_tenant_visible(tenant_id));
+
+-- ==============================================================================
+-- roles
+
+ALTER TABLE roles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE roles FORCE ROW LEVEL SECURITY;
+
+-- Built-in roles are shared and can be read by everyone, but only written
+-- when no tenant is set.
+CREATE POLICY roles_tenant ON roles
+	USING (tenant_id IS NULL OR app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
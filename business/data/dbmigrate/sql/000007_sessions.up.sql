@@ -0,0 +1,36 @@
+-- Description: Create tables refresh_tokens and revoked_tokens
+
+-- Refresh tokens are rotated on every use. Tokens rotated from the same
+-- login share a session id so the whole chain can be revoked at once.
+CREATE TABLE refresh_tokens (
+	token_id   UUID      NOT NULL,
+	session_id UUID      NOT NULL,
+	tenant_id  UUID      NOT NULL,
+	user_id    UUID      NOT NULL,
+	token_hash BYTEA     NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	used_at    TIMESTAMP NULL,
+	revoked_at TIMESTAMP NULL,
+	created_at TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (token_id),
+	UNIQUE (token_hash),
+	FOREIGN KEY (tenant_id, user_id) REFERENCES users(tenant_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX refresh_tokens_session_id_idx ON refresh_tokens (session_id);
+
+ALTER TABLE refresh_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE refresh_tokens FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY refresh_tokens_tenant ON refresh_tokens
+	USING (app_tenant_visible(tenant_id));
+
+-- Access tokens that were revoked before they expired, by their jti. Rows
+-- can be removed once the token would have expired anyway.
+CREATE TABLE revoked_tokens (
+	jti        TEXT      NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (jti)
+);
@@ -0,0 +1,2 @@
+DROP POLICY IF EXISTS authorization_decisions_tenant ON authorization_decisions;
+DROP TABLE IF EXISTS authorization_decisions;
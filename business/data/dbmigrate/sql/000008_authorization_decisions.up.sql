@@ -0,0 +1,27 @@
+-- Description: Create table authorization_decisions
+
+-- Every authorization decision, kept for audits. The subject and the target
+-- user aren't foreign keys, the record outlives the users it mentions.
+CREATE TABLE authorization_decisions (
+	decision_id    UUID      NOT NULL,
+	tenant_id      UUID      NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+	subject        TEXT      NOT NULL,
+	rule           TEXT      NOT NULL,
+	user_id        UUID      NULL,
+	route          TEXT      NOT NULL,
+	allowed        BOOLEAN   NOT NULL,
+	reason         TEXT      NOT NULL,
+	policy_version TEXT      NOT NULL,
+	latency_us     BIGINT    NOT NULL,
+	created_at     TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (decision_id)
+);
+
+CREATE INDEX authorization_decisions_tenant_created_at_idx ON authorization_decisions (tenant_id, created_at);
+
+ALTER TABLE authorization_decisions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE authorization_decisions FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY authorization_decisions_tenant ON authorization_decisions
+	USING (app_tenant_visible(tenant_id));
@@ -0,0 +1,27 @@
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+	REVOKE USAGE, SELECT ON SEQUENCES FROM sales_app, sales_maintenance;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+	REVOKE SELECT, INSERT, UPDATE, DELETE ON TABLES FROM sales_app, sales_maintenance;
+
+REVOKE ALL ON ALL SEQUENCES IN SCHEMA public FROM sales_app, sales_maintenance;
+REVOKE ALL ON ALL TABLES IN SCHEMA public FROM sales_app, sales_maintenance;
+REVOKE USAGE ON SCHEMA public FROM sales_app, sales_maintenance;
+
+DROP ROLE IF EXISTS sales_maintenance;
+DROP ROLE IF EXISTS sales_app;
+
+DROP POLICY IF EXISTS appointments_owner ON appointments;
+
+DROP POLICY IF EXISTS organizations_tenant ON organizations;
+
+CREATE POLICY organizations_tenant ON organizations
+	USING (app_tenant_visible(organization_id))
+	WITH CHECK (true);
+
+DROP FUNCTION IF EXISTS app_user_visible(UUID);
+
+CREATE OR REPLACE FUNCTION app_tenant_visible(row_tenant UUID) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+	SELECT NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+		OR row_tenant = NULLIF(current_setting('app.tenant_id', true), '')::UUID
+$$;
@@ -0,0 +1,60 @@
+-- Description: Run the service as roles the row level security applies to
+
+-- A row is only visible once a tenant is set. Whatever runs without one,
+-- a statement outside of a request or a request that forgot to say who it
+-- is for, sees nothing rather than every tenant.
+CREATE OR REPLACE FUNCTION app_tenant_visible(row_tenant UUID) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+	SELECT COALESCE(row_tenant = NULLIF(current_setting('app.tenant_id', true), '')::UUID, false)
+$$;
+
+-- A user's own rows are visible to them and to whoever was authorized to
+-- act on them, app.target_user_id.
+CREATE FUNCTION app_user_visible(row_user UUID) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+	SELECT COALESCE(row_user = NULLIF(current_setting('app.user_id', true), '')::UUID, false)
+		OR COALESCE(row_user = NULLIF(current_setting('app.target_user_id', true), '')::UUID, false)
+$$;
+
+-- Organizations are created by the maintenance role, the service can only
+-- touch its own.
+DROP POLICY organizations_tenant ON organizations;
+
+CREATE POLICY organizations_tenant ON organizations
+	USING (app_tenant_visible(organization_id));
+
+-- On top of the tenant, appointments belong to their owner.
+CREATE POLICY appointments_owner ON appointments AS RESTRICTIVE
+	USING (app_user_visible(owner_id));
+
+-- ==============================================================================
+-- roles
+
+-- sales_app is what the service runs requests as, every policy applies to
+-- it. sales_maintenance bypasses the policies for the work that can't know
+-- the tenant up front: logging in, refreshing tokens, opening calendar
+-- feeds, loading the role registry and creating organizations. Neither is
+-- a superuser or owns the tables. Their passwords are set by sales-admin.
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'sales_app') THEN
+		CREATE ROLE sales_app LOGIN NOSUPERUSER NOBYPASSRLS;
+	END IF;
+
+	IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'sales_maintenance') THEN
+		CREATE ROLE sales_maintenance LOGIN NOSUPERUSER BYPASSRLS;
+	END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO sales_app, sales_maintenance;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO sales_app, sales_maintenance;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO sales_app, sales_maintenance;
+
+-- Tables created by later migrations are covered as well.
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+	GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO sales_app, sales_maintenance;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+	GRANT USAGE, SELECT ON SEQUENCES TO sales_app, sales_maintenance;
+
+REVOKE ALL ON schema_migrations FROM sales_app, sales_maintenance;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS authorization_decisions_created_at_idx;
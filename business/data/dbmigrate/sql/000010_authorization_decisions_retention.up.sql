@@ -0,0 +1,5 @@
+-- Description: Index authorization_decisions for removing old decisions
+
+-- Decisions are kept for a retention period. Removing the old ones looks
+-- at every tenant, which the tenant index can't help with.
+CREATE INDEX authorization_decisions_created_at_idx ON authorization_decisions (created_at);
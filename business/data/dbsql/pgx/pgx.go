@@ -17,14 +17,16 @@ import (
 )
 
 const (
-	uniqueViolation = "23505"
-	undefinedTable  = "42P01"
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	undefinedTable      = "42P01"
 )
 
 // Set of error variables for CRUD operations.
 var (
 	ErrDBNotFound        = sql.ErrNoRows
 	ErrDBDuplicatedEntry = errors.New("duplicated entry")
+	ErrDBForeignKey      = errors.New("foreign key violation")
 	ErrUndefinedTable    = errors.New("undefined table")
 )
 
@@ -116,16 +118,12 @@ func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 		log.Infoc(ctx, 4, "database.NamedExecContext", "query", q)
 	}
 
-	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
-		if pqerr, ok := err.(*pgconn.PgError); ok {
-			switch pqerr.Code {
-			case undefinedTable:
-				return ErrUndefinedTable
-			case uniqueViolation:
-				return ErrDBDuplicatedEntry
-			}
-		}
+	err := withSession(ctx, db, func(db sqlx.ExtContext) error {
+		_, err := sqlx.NamedExecContext(ctx, db, query, data)
 		return err
+	})
+	if err != nil {
+		return mapError(err)
 	}
 
 	return nil
@@ -151,47 +149,46 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 
 	log.Infoc(ctx, 5, "database.NamedQueryStruct", "query", q)
 
-	var rows *sqlx.Rows
-	var err error
+	return withSession(ctx, db, func(db sqlx.ExtContext) error {
+		var rows *sqlx.Rows
+		var err error
 
-	switch withIn {
-	case true:
-		rows, err = func() (*sqlx.Rows, error) {
-			named, args, err := sqlx.Named(query, data)
-			if err != nil {
-				return nil, err
-			}
+		switch withIn {
+		case true:
+			rows, err = func() (*sqlx.Rows, error) {
+				named, args, err := sqlx.Named(query, data)
+				if err != nil {
+					return nil, err
+				}
 
-			query, args, err := sqlx.In(named, args...)
-			if err != nil {
-				return nil, err
-			}
+				query, args, err := sqlx.In(named, args...)
+				if err != nil {
+					return nil, err
+				}
 
-			query = db.Rebind(query)
-			return db.QueryxContext(ctx, query, args...)
-		}()
+				query = db.Rebind(query)
+				return db.QueryxContext(ctx, query, args...)
+			}()
 
-	default:
-		rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
-	}
+		default:
+			rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
+		}
 
-	if err != nil {
-		if pqerr, ok := err.(*pgconn.PgError); ok && pqerr.Code == undefinedTable {
-			return ErrUndefinedTable
+		if err != nil {
+			return mapError(err)
 		}
-		return err
-	}
-	defer rows.Close()
+		defer rows.Close()
 
-	if !rows.Next() {
-		return ErrDBNotFound
-	}
+		if !rows.Next() {
+			return ErrDBNotFound
+		}
 
-	if err := rows.StructScan(dest); err != nil {
-		return err
-	}
+		if err := rows.StructScan(dest); err != nil {
+			return err
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any, dest *[]T, withIn bool) error {
@@ -199,49 +196,48 @@ func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.Ext
 
 	log.Infoc(ctx, 5, "database.NamedQuerySlice", "query", q)
 
-	var rows *sqlx.Rows
-	var err error
+	return withSession(ctx, db, func(db sqlx.ExtContext) error {
+		var rows *sqlx.Rows
+		var err error
 
-	switch withIn {
-	case true:
-		rows, err = func() (*sqlx.Rows, error) {
-			named, args, err := sqlx.Named(query, data)
-			if err != nil {
-				return nil, err
-			}
+		switch withIn {
+		case true:
+			rows, err = func() (*sqlx.Rows, error) {
+				named, args, err := sqlx.Named(query, data)
+				if err != nil {
+					return nil, err
+				}
 
-			query, args, err := sqlx.In(named, args...)
-			if err != nil {
-				return nil, err
-			}
+				query, args, err := sqlx.In(named, args...)
+				if err != nil {
+					return nil, err
+				}
 
-			query = db.Rebind(query)
-			return db.QueryxContext(ctx, query, args...)
-		}()
+				query = db.Rebind(query)
+				return db.QueryxContext(ctx, query, args...)
+			}()
 
-	default:
-		rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
-	}
+		default:
+			rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
+		}
 
-	if err != nil {
-		if pqerr, ok := err.(*pgconn.PgError); ok && pqerr.Code == undefinedTable {
-			return ErrUndefinedTable
+		if err != nil {
+			return mapError(err)
 		}
-		return err
-	}
-	defer rows.Close()
+		defer rows.Close()
 
-	var slice []T
-	for rows.Next() {
-		v := new(T)
-		if err := rows.StructScan(v); err != nil {
-			return err
+		var slice []T
+		for rows.Next() {
+			v := new(T)
+			if err := rows.StructScan(v); err != nil {
+				return err
+			}
+			slice = append(slice, *v)
 		}
-		slice = append(slice, *v)
-	}
-	*dest = slice
+		*dest = slice
 
-	return nil
+		return nil
+	})
 }
 
 // queryString provides a pretty print version of the query and parameters.
@@ -269,3 +265,19 @@ func queryString(query string, args any) string {
 
 	return strings.Trim(query, " ")
 }
+
+// mapError converts the Postgres errors callers act on into the package's
+// error values, any other error is returned as is.
+func mapError(err error) error {
+	if pqerr, ok := err.(*pgconn.PgError); ok {
+		switch pqerr.Code {
+		case undefinedTable:
+			return ErrUndefinedTable
+		case uniqueViolation:
+			return ErrDBDuplicatedEntry
+		case foreignKeyViolation:
+			return ErrDBForeignKey
+		}
+	}
+	return err
+}
@@ -1,7 +1,10 @@
 package pgx
 
 import (
+	"context"
 	"fmt"
+	"sales-api/business/data/actor"
+	"sales-api/business/data/tenant"
 	"sales-api/business/data/transaction"
 
 	"github.com/jmoiron/sqlx"
@@ -21,10 +24,82 @@ func NewBeginner(sqlxDB *sqlx.DB) transaction.Beginner {
 }
 
 // Begin start a transaction and returns a value that implements
-// the core transactor interface.
+// the core transactor interface. The tenant and users found in the context
+// are written to the session variables the row level security policies
+// check for the life of the transaction.
+func (pgx *dbBeginner) Begin(ctx context.Context) (transaction.Transaction, error) {
+	tx, err := pgx.sqlxDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setSession(ctx, tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// setSession copies the tenant, the acting user and the user acted on from
+// the context into the transaction scoped settings app.tenant_id,
+// app.user_id and app.target_user_id. Values that are missing are left
+// empty, which the policies treat as seeing nothing.
+func setSession(ctx context.Context, tx *sqlx.Tx) error {
+	var tenantID, userID, targetID string
+	if id, ok := tenant.Get(ctx); ok {
+		tenantID = id.String()
+	}
+	if id, ok := actor.Get(ctx); ok {
+		userID = id.String()
+	}
+	if id, ok := actor.GetTarget(ctx); ok {
+		targetID = id.String()
+	}
+
+	const q = `
+	SELECT
+		set_config('app.tenant_id', $1, true),
+		set_config('app.user_id', $2, true),
+		set_config('app.target_user_id', $3, true)`
+
+	if _, err := tx.ExecContext(ctx, q, tenantID, userID, targetID); err != nil {
+		return fmt.Errorf("setting session: %w", err)
+	}
+
+	return nil
+}
+
+// withSession runs fn against db with the session variables set from the
+// context. Statements outside of a transaction get one of their own, as the
+// variables only live as long as a transaction. Within a transaction they
+// were set by Begin, and without a tenant there is nothing to set.
+func withSession(ctx context.Context, db sqlx.ExtContext, fn func(db sqlx.ExtContext) error) error {
+	sqlxDB, ok := db.(*sqlx.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	if _, ok := tenant.Get(ctx); !ok {
+		return fn(db)
+	}
+
+	tx, err := sqlxDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := setSession(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-func (pgx *dbBeginner) Begin() (transaction.Transaction, error) {
-	return pgx.sqlxDB.Beginx()
+	return tx.Commit()
 }
 
 // GetExtContext is a helper function that extracts the sqlx value
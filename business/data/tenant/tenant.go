@@ -0,0 +1,42 @@
+// Package tenant provides support for carrying the organization a request
+// acts for through the context, so stores can scope every query to it.
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrMissing is returned when a store is used without a tenant in the
+// context. Stores fail closed rather than read across organizations.
+var ErrMissing = errors.New("tenant missing from context")
+
+type ctxKey int
+
+const tenantKey ctxKey = 1
+
+// Set stores the id of the tenant in the context.
+func Set(ctx context.Context, tenantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// Get retrieves the id of the tenant from the context.
+func Get(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(tenantKey).(uuid.UUID)
+	if !ok || v == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return v, true
+}
+
+// ID retrieves the id of the tenant from the context, returning ErrMissing
+// when there isn't one.
+func ID(ctx context.Context) (uuid.UUID, error) {
+	tenantID, ok := Get(ctx)
+	if !ok {
+		return uuid.Nil, ErrMissing
+	}
+	return tenantID, nil
+}
@@ -6,8 +6,21 @@ import (
 	"fmt"
 	"math/rand"
 	"net/mail"
+	"sales-api/business/core/appointment"
+	"sales-api/business/core/appointment/stores/appointmentdb"
+	"sales-api/business/core/decision"
+	"sales-api/business/core/decision/stores/decisiondb"
+	"sales-api/business/core/department"
+	"sales-api/business/core/department/stores/departmentdb"
+	"sales-api/business/core/organization"
+	"sales-api/business/core/organization/stores/organizationdb"
+	"sales-api/business/core/role"
+	"sales-api/business/core/role/stores/roledb"
+	"sales-api/business/core/session"
+	"sales-api/business/core/session/stores/sessiondb"
 	"sales-api/business/core/user"
 	"sales-api/business/core/user/stores/userdb"
+	"sales-api/business/data/tenant"
 	"sales-api/business/web/v1/auth"
 	"sales-api/foundation/logger"
 	"sales-api/foundation/web"
@@ -15,9 +28,13 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
+// DefaultTenantID is the organization the seed data belongs to.
+var DefaultTenantID = uuid.MustParse("9f0c5e3a-2d4b-4c8e-8a1f-6b7d3e2c1a05")
+
 // Test owns state for running and shutting down tests.
 type Test struct {
 	TestDatabase
@@ -26,6 +43,8 @@ type Test struct {
 	TearDown func()
 	tb       testing.TB
 	Auth     *auth.Auth
+	Keys     auth.KeySet
+	Caches   Caches
 }
 
 func New(tb testing.TB) *Test {
@@ -51,14 +70,29 @@ func New(tb testing.TB) *Test {
 		return web.GetTraceID(ctx)
 	})
 
-	coreAPIs := newCoreAPIs(log, testDB.DB)
+	caches := Caches{
+		User: user.NewEnabledCache(),
+		Role: role.NewCache(roledb.NewRepository(log, testDB.MaintenanceDB)),
+	}
+
+	coreAPIs := newCoreAPIs(log, caches, testDB.DB, testDB.MaintenanceDB)
 
 	tb.Log("Ready for testing ...")
 	//  ------------------------------------------------------------
 
+	ks := &keyStore{}
+
 	cfg := auth.Config{
-		Log:       log,
-		KeyLookup: &keyStore{},
+		Log:            log,
+		KeyLookup:      ks,
+		ActiveKID:      kid,
+		Issuer:         "service project",
+		Audience:       "sales-api",
+		ReportingChain: coreAPIs.Department,
+		Permissions:    coreAPIs.Role,
+		Users:          coreAPIs.User,
+		Revocations:    coreAPIs.Session,
+		Decisions:      []auth.DecisionSink{coreAPIs.Decision},
 	}
 
 	auth, err := auth.New(cfg)
@@ -83,25 +117,59 @@ func New(tb testing.TB) *Test {
 		TearDown:     teardown,
 		tb:           tb,
 		Auth:         auth,
+		Keys:         ks,
+		Caches:       caches,
 	}
 	return &test
 }
 
 // ====================================================================
-// CoreAPIs represents all the core api's needed for testing.
+// CoreAPIs represents all the core api's needed for testing. They connect
+// the way the service does, organizations and sessions are maintenance
+// work and bypass row level security.
 type CoreAPIs struct {
-	User *user.Core
+	Organization *organization.Core
+	User         *user.Core
+	Department   *department.Core
+	Role         *role.Core
+	Session      *session.Core
+	Appointment  *appointment.Core
+	Decision     *decision.Core
+}
+
+// Caches represents the caches the cores of a test share.
+type Caches struct {
+	User *user.EnabledCache
+	Role *role.Cache
 }
 
-func newCoreAPIs(log *logger.Logger, db *sqlx.DB) CoreAPIs {
-	usrCore := user.NewCore(log, userdb.NewRepository(log, db))
+func newCoreAPIs(log *logger.Logger, caches Caches, db *sqlx.DB, maintDB *sqlx.DB) CoreAPIs {
+	orgCore := organization.NewCore(log, organizationdb.NewRepository(log, maintDB))
+	usrCore := user.NewCore(log, caches.User, userdb.NewRepository(log, db))
+	depCore := department.NewCore(log, usrCore, departmentdb.NewRepository(log, db))
+	rolCore := role.NewCore(log, caches.Role, roledb.NewRepository(log, db))
+	sesCore := session.NewCore(log, sessiondb.NewRepository(log, maintDB))
+	apptCore := appointment.NewCore(log, appointmentdb.NewRepository(log, db))
+	decCore := decision.NewCore(log, decisiondb.NewRepository(log, db))
 	return CoreAPIs{
-		User: usrCore,
+		Organization: orgCore,
+		User:         usrCore,
+		Department:   depCore,
+		Role:         rolCore,
+		Session:      sesCore,
+		Appointment:  apptCore,
+		Decision:     decCore,
 	}
 }
 
 // ============================================================
 
+// Context returns a context that acts for the organization the seed data
+// belongs to, as the stores require a tenant.
+func (test *Test) Context() context.Context {
+	return tenant.Set(context.Background(), DefaultTenantID)
+}
+
 func (test *Test) TokenV1(email, password string) (string, error) {
 	test.tb.Logf("Generating %q token for test ...", email)
 
@@ -110,22 +178,24 @@ func (test *Test) TokenV1(email, password string) (string, error) {
 		return "", err
 	}
 
-	store := userdb.NewRepository(test.Log, test.DB)
-	dbUsr, err := store.QueryByEmail(context.Background(), *addr)
+	store := userdb.NewRepository(test.Log, test.MaintenanceDB)
+	dbUsr, err := store.QueryByEmailAcrossTenants(context.Background(), *addr)
 	if err != nil {
 		return "", err
 	}
 
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   dbUsr.ID.String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
-		Roles: dbUsr.Roles,
+		TenantID: dbUsr.TenantID,
+		Roles:    dbUsr.Roles,
 	}
 
-	token, err := test.Auth.GenerateToken(kid, claims)
+	token, err := test.Auth.GenerateToken(claims)
 
 	if err != nil {
 		test.tb.Fatal(err)
@@ -147,6 +217,10 @@ func (ks *keyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+func (ks *keyStore) ActiveKIDs() []string {
+	return []string{kid}
+}
+
 const (
 	kid = "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3Hd9k9NQ"
 
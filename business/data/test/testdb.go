@@ -13,9 +13,12 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
+// TestDatabase connects as the roles the service uses. DB is subject to row
+// level security, MaintenanceDB bypasses it.
 type TestDatabase struct {
-	DB        *sqlx.DB
-	container testcontainers.Container
+	DB            *sqlx.DB
+	MaintenanceDB *sqlx.DB
+	container     testcontainers.Container
 }
 
 func (d *TestDatabase) TearDown() {
@@ -23,19 +26,33 @@ func (d *TestDatabase) TearDown() {
 }
 
 func SetUpTestDatabase(ctx context.Context, dbName string) *TestDatabase {
-	container, db, err := createPostgresContainer(ctx, dbName)
+	container, connStr, err := createPostgresContainer(ctx, dbName)
 	if err != nil {
 		log.Fatalf("createPostgresContainer failed %v", err)
 	}
+
+	db, err := sqlx.Open("pgx", connStr(dbmigrate.AppRole))
+	if err != nil {
+		log.Fatalf("connecting as %s failed %v", dbmigrate.AppRole, err)
+	}
+	maintDB, err := sqlx.Open("pgx", connStr(dbmigrate.MaintenanceRole))
+	if err != nil {
+		log.Fatalf("connecting as %s failed %v", dbmigrate.MaintenanceRole, err)
+	}
+
 	return &TestDatabase{
-		container: container,
-		DB:        db,
+		container:     container,
+		DB:            db,
+		MaintenanceDB: maintDB,
 	}
 }
 
 // ====================================================================================
 
-func createPostgresContainer(ctx context.Context, dbName string) (container testcontainers.Container, db *sqlx.DB, err error) {
+// createPostgresContainer starts the database and migrates it as the
+// superuser. It returns how to connect as one of the roles the migrations
+// created.
+func createPostgresContainer(ctx context.Context, dbName string) (container testcontainers.Container, connStr func(role string) string, err error) {
 	containerPort := "5432"
 	req := testcontainers.ContainerRequest{
 		Image: "postgres:16.4",
@@ -67,13 +84,26 @@ func createPostgresContainer(ctx context.Context, dbName string) (container test
 		return container, nil, fmt.Errorf("failed to get container external port: %v", err)
 	}
 	log.Println("container ready and running at port: ", p.Port())
-	connStr := fmt.Sprintf("postgresql://root:password@%s:%s/%s?sslmode=disable", host, p.Port(), dbName)
+	connStr = func(role string) string {
+		return fmt.Sprintf("postgresql://%s:password@%s:%s/%s?sslmode=disable", role, host, p.Port(), dbName)
+	}
 
-	db, err = sqlx.Open("pgx", connStr)
+	db, err := sqlx.Open("pgx", connStr("root"))
 	if err != nil {
-		return container, db, fmt.Errorf("failed to establish database connection: %v", err)
+		return container, nil, fmt.Errorf("failed to establish database connection: %v", err)
 	}
+	defer db.Close()
+
 	source := "file:///Users/mogan/workspace/src/github.com/demorgan/sales-api/business/data/dbmigrate/sql"
-	err = dbmigrate.Migration(ctx, source, db)
-	return
+	if err := dbmigrate.Migration(ctx, source, db); err != nil {
+		return container, nil, err
+	}
+
+	for _, role := range []string{dbmigrate.AppRole, dbmigrate.MaintenanceRole} {
+		if err := dbmigrate.SetPassword(ctx, db, role, "password"); err != nil {
+			return container, nil, err
+		}
+	}
+
+	return container, connStr, nil
 }
@@ -1,6 +1,9 @@
 package transaction
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 // Transaction represents a value that can commit or rollback a transaction.
 
@@ -9,9 +12,11 @@ type Transaction interface {
 	Rollback() error
 }
 
-// Beginner represents a value that can begin a transaction.
+// Beginner represents a value that can begin a transaction. The context
+// carries whatever the request knows about who is calling, so the
+// implementation can pass it on to the database.
 type Beginner interface {
-	Begin() (Transaction, error)
+	Begin(ctx context.Context) (Transaction, error)
 }
 
 // =============================================================================
@@ -20,13 +25,58 @@ type ctxKey int
 
 const trKey ctxKey = 2
 
+// state is what the context carries for a transaction, the work to run once
+// it commits included.
+type state struct {
+	tx          Transaction
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
 // Set stores a value that can manage a transaction.
 func Set(ctx context.Context, tx Transaction) context.Context {
-	return context.WithValue(ctx, trKey, tx)
+	return context.WithValue(ctx, trKey, &state{tx: tx})
 }
 
 // Get retrieves the value that can manage a transaction.
 func Get(ctx context.Context) (Transaction, bool) {
-	v, ok := ctx.Value(trKey).(Transaction)
-	return v, ok
+	s, ok := ctx.Value(trKey).(*state)
+	if !ok {
+		return nil, false
+	}
+	return s.tx, true
+}
+
+// AfterCommit registers fn to run once the transaction in the context has
+// committed, for work such as dropping cached values that must not be read
+// back before the change is visible. Without a transaction fn runs right
+// away.
+func AfterCommit(ctx context.Context, fn func()) {
+	s, ok := ctx.Value(trKey).(*state)
+	if !ok {
+		fn()
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.afterCommit = append(s.afterCommit, fn)
+}
+
+// Committed runs the functions registered with AfterCommit. It is called by
+// whoever commits the transaction in the context.
+func Committed(ctx context.Context) {
+	s, ok := ctx.Value(trKey).(*state)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	fns := s.afterCommit
+	s.afterCommit = nil
+	s.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
 }
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"sales-api/business/core/user"
+	"sales-api/business/data/tenant"
 	"sales-api/foundation/logger"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -17,9 +19,14 @@ import (
 // ErrForbidden is returned when a auth issue is identified.
 var ErrForbidden = errors.New("attempted action is not allowed")
 
+// Claims represents the authorization claims transmitted via a JWT. The
+// tenant is the organization the user belongs to, the session is the login
+// the token was issued in, so ending it revokes every token it issued.
 type Claims struct {
 	jwt.RegisteredClaims
-	Roles []user.Role `json:"roles"`
+	TenantID  uuid.UUID   `json:"tenant_id"`
+	SessionID uuid.UUID   `json:"sid"`
+	Roles     []user.Role `json:"roles"`
 }
 
 // KeyLookup declares a method set of behavior for looking up
@@ -30,55 +37,158 @@ type KeyLookup interface {
 	PublicKey(kid string) (key string, err error)
 }
 
-// Config represents information required to initialize auth.
+// KeySet declares a method set of behavior for listing the keys that can
+// currently verify tokens, so they can be published.
+type KeySet interface {
+	ActiveKIDs() []string
+	PublicKey(kid string) (key string, err error)
+}
+
+// ReportingChainLookup declares a method set of behavior for looking up the
+// managers a user reports to, directly or through parent departments.
+type ReportingChainLookup interface {
+	ReportingChain(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// PermissionLookup declares a method set of behavior for looking up the
+// permissions granted by each role, keyed by role name.
+type PermissionLookup interface {
+	Permissions(ctx context.Context) (map[string][]string, error)
+}
+
+// RevocationLookup declares a method set of behavior for checking whether an
+// access token was revoked before it expired, by its id or the session it
+// was issued in.
+type RevocationLookup interface {
+	IsRevoked(ctx context.Context, jti string, sessionID uuid.UUID) (bool, error)
+}
+
+// UserLookup declares a method set of behavior for checking whether a user
+// may still use the service.
+type UserLookup interface {
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// Config represents information required to initialize auth. Tokens this
+// service signs carry Issuer and Audience. Tokens are accepted from Issuer
+// and any of the AcceptedIssuers, and must name Audience when one is set.
+// Leeway allows for clock skew when checking exp, nbf and iat. Rules are
+// evaluated against the policy bundle at PolicyPath, a directory or a
+// tarball, or against the embedded policies when there is none.
 type Config struct {
-	Log       *logger.Logger
-	KeyLookup KeyLookup
-	Issuer    string
+	Log             *logger.Logger
+	KeyLookup       KeyLookup
+	ActiveKID       string
+	Issuer          string
+	AcceptedIssuers []string
+	Audience        string
+	Leeway          time.Duration
+	PolicyPath      string
+	ReportingChain  ReportingChainLookup
+	Permissions     PermissionLookup
+	Revocations     RevocationLookup
+	Users           UserLookup
+	Decisions       []DecisionSink
 }
 
 // Auth is used to authenticate clients. It can generate a token for a
 // set of user claims and recreate the claims by parsing the token.
 type Auth struct {
-	log       *logger.Logger
-	keyLookup KeyLookup
-	method    jwt.SigningMethod
-	parser    *jwt.Parser
-	issuer    string
-	mu        sync.RWMutex
-	cache     map[string]string
+	log            *logger.Logger
+	keyLookup      KeyLookup
+	activeKID      string
+	reportingChain ReportingChainLookup
+	permissions    PermissionLookup
+	revocations    RevocationLookup
+	users          UserLookup
+	decisions      []DecisionSink
+	parser         *jwt.Parser
+	issuer         string
+	issuers        map[string]bool
+	audience       string
+	policyPath     string
+	policyMu       sync.RWMutex
+	policy         policySet
+	mu             sync.RWMutex
+	cache          map[string]string
 }
 
-// New creates an Auth to support authentication/authorization.
+// New creates an Auth to support authentication/authorization. Tokens are
+// signed with the active key, any other key the lookup knows about can still
+// be used to verify them.
 func New(cfg Config) (*Auth, error) {
+	if cfg.ActiveKID == "" {
+		return nil, errors.New("active kid missing")
+	}
+
+	if _, err := cfg.KeyLookup.PrivateKey(cfg.ActiveKID); err != nil {
+		return nil, fmt.Errorf("active kid[%s]: %w", cfg.ActiveKID, err)
+	}
 
 	a := Auth{
-		log:       cfg.Log,
-		keyLookup: cfg.KeyLookup,
-		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
-		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
-		issuer:    cfg.Issuer,
-		cache:     make(map[string]string),
+		log:            cfg.Log,
+		keyLookup:      cfg.KeyLookup,
+		activeKID:      cfg.ActiveKID,
+		reportingChain: cfg.ReportingChain,
+		permissions:    cfg.Permissions,
+		revocations:    cfg.Revocations,
+		users:          cfg.Users,
+		decisions:      cfg.Decisions,
+		parser:         jwt.NewParser(parserOptions(cfg)...),
+		issuer:         cfg.Issuer,
+		issuers:        make(map[string]bool),
+		audience:       cfg.Audience,
+		policyPath:     cfg.PolicyPath,
+		cache:          make(map[string]string),
+	}
+
+	for _, iss := range append([]string{cfg.Issuer}, cfg.AcceptedIssuers...) {
+		if iss != "" {
+			a.issuers[iss] = true
+		}
+	}
+
+	ps, err := embeddedPolicies(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("preparing queries: %w", err)
+	}
+	a.policy = ps
+
+	// A bundle that can't be used doesn't stop the service, the embedded
+	// policies are used until a reload picks up a good one.
+	if _, err := a.ReloadPolicies(context.Background()); err != nil {
+		a.log.Error(context.Background(), "auth: policy bundle, using embedded policies", "path", cfg.PolicyPath, "ERROR", err)
 	}
 
 	return &a, nil
 
 }
 
-func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
-	token := jwt.NewWithClaims(a.method, claims)
-	token.Header["kid"] = kid
+// GenerateToken signs a token for the claims with the active key, using the
+// signing method that goes with the type of key. The kid header tells
+// whoever verifies it which public key to use. The issuer and audience are
+// filled in when the claims don't have them.
+func (a *Auth) GenerateToken(claims Claims) (string, error) {
+	if claims.Issuer == "" {
+		claims.Issuer = a.issuer
+	}
+	if len(claims.Audience) == 0 && a.audience != "" {
+		claims.Audience = jwt.ClaimStrings{a.audience}
+	}
 
-	privateKeyPEM, err := a.keyLookup.PrivateKey(kid)
+	privateKeyPEM, err := a.keyLookup.PrivateKey(a.activeKID)
 	if err != nil {
 		return "", fmt.Errorf("private key: %w", err)
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	privateKey, method, err := parsePrivateKey(privateKeyPEM)
 	if err != nil {
 		return "", fmt.Errorf("parsing private pem: %w", err)
 	}
 
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = a.activeKID
+
 	str, err := token.SignedString(privateKey)
 	if err != nil {
 		return "", fmt.Errorf("signing token: %w", err)
@@ -96,38 +206,32 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		return Claims{}, errors.New("expected authorization header format: Bearer <token>")
 	}
 
-	var claims Claims
+	// The signature is checked against the public key named by the kid
+	// header. The token has to be signed with the method that goes with that
+	// key, whatever its own header claims. The parser checks the time based
+	// claims and the audience along the way.
 
-	token, _, err := a.parser.ParseUnverified(parts[1], &claims)
+	var claims Claims
 
-	if err != nil {
+	if _, err := a.parser.ParseWithClaims(parts[1], &claims, a.verificationKey); err != nil {
 		return Claims{}, fmt.Errorf("error parsing token: %w", err)
 	}
 
-	// Perform an extra level of authentication verification with OPA.
-
-	kidRaw, exists := token.Header["kid"]
-	if !exists {
-		return Claims{}, fmt.Errorf("kId missing from header: %w", err)
-	}
-	kid, ok := kidRaw.(string)
-	if !ok {
-		return Claims{}, fmt.Errorf("kid malformed: %w", err)
+	if len(a.issuers) > 0 && !a.issuers[claims.Issuer] {
+		return Claims{}, fmt.Errorf("issuer %q not accepted", claims.Issuer)
 	}
 
-	pem, err := a.publicKeyLookup(kid)
-	if err != nil {
-		return Claims{}, fmt.Errorf("failed to fetch public key: %w", err)
-	}
+	// Every store is scoped to a tenant, a token without one is of no use.
 
-	input := map[string]any{
-		"Key":   pem,
-		"Token": parts[1],
-		"ISS":   a.issuer,
+	if claims.TenantID == uuid.Nil {
+		return Claims{}, errors.New("tenant missing from claims")
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, opaAuthentication, RuleAuthenticate, input); err != nil {
-		return Claims{}, fmt.Errorf("authentication failed : %w", err)
+	// A token that was revoked, on logout for instance, is refused even
+	// though it hasn't expired.
+
+	if err := a.isRevoked(ctx, claims); err != nil {
+		return Claims{}, err
 	}
 
 	// Check the database for this user to verify they are still enabled.
@@ -139,17 +243,45 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 
 }
 
-// Authorize attempts to authorize the user with the provided input roles, if
-// none of the input roles are within the user's claims, we return an error
-// otherwise the user is authorized.
-func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
+// Authorize attempts to authorize the user against the specified rule using
+// the permissions granted by the roles in the user's claims. If the rule isn't
+// satisfied we return an error otherwise the user is authorized. Every
+// decision is handed to the decision sinks.
+func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) (err error) {
+	start := time.Now()
+	ps := a.currentPolicy()
+
+	defer func() {
+		a.recordDecision(ctx, claims, userID, rule, ps.version, start, err)
+	}()
+
+	permissions, err := a.rolePermissions(ctx)
+	if err != nil {
+		return fmt.Errorf("permissions: %w", err)
+	}
+
 	input := map[string]any{
-		"Roles":   claims.Roles,
-		"Subject": claims.Subject,
-		"UserID":  userID,
+		"Roles":       claims.Roles,
+		"Permissions": permissions,
+		"Subject":     claims.Subject,
+		"UserID":      userID,
+	}
+
+	// Only look up the managers when the rule needs them since it costs a
+	// trip to the database.
+	var managers []string
+	if rulesWithManagers[rule] {
+		managers, err = a.managersOf(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("reporting chain: %w", err)
+		}
+		input["Managers"] = managers
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, opaAuthorization, rule, input); err != nil {
+	if err := a.opaPolicyEvaluation(ctx, ps, rule, input); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return fmt.Errorf("%w: %s", ErrForbidden, explainDenial(rule, claims, permissions, userID, managers))
+		}
 		return fmt.Errorf("rego evaluation failed : %w", err)
 	}
 
@@ -158,17 +290,12 @@ func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, r
 
 //=====================================================================================================
 
-// opaPolicyEvaluation asks opa to evaulate the token against the specified token
-// policy and public key.
-func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule string, input any) error {
-	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
-
-	q, err := rego.New(
-		rego.Query(query),
-		rego.Module("policy.rego", opaPolicy),
-	).PrepareForEval(ctx)
-	if err != nil {
-		return err
+// opaPolicyEvaluation asks opa to evaulate the input against the prepared
+// query for the specified rule in the policy set.
+func (a *Auth) opaPolicyEvaluation(ctx context.Context, ps policySet, rule string, input any) error {
+	q, exists := ps.queries[rule]
+	if !exists {
+		return fmt.Errorf("unknown rule %q", rule)
 	}
 
 	results, err := q.Eval(ctx, rego.EvalInput(input))
@@ -181,13 +308,62 @@ func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule s
 	}
 
 	result, ok := results[0].Bindings["x"].(bool)
-	if !ok || !result {
+	if !ok {
 		return fmt.Errorf("bindings results[%v] ok[%v]", results, ok)
 	}
+	if !result {
+		return ErrForbidden
+	}
 
 	return nil
 }
 
+// parserOptions returns the validation the parser applies to every token.
+// A token has to expire, and can't be used before it was issued or before
+// its not before time.
+func parserOptions(cfg Config) []jwt.ParserOption {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return opts
+}
+
+// verificationKey returns the public key the token has to be verified with.
+func (a *Auth) verificationKey(token *jwt.Token) (any, error) {
+	kidRaw, exists := token.Header["kid"]
+	if !exists {
+		return nil, errors.New("kid missing from header")
+	}
+	kid, ok := kidRaw.(string)
+	if !ok {
+		return nil, errors.New("kid malformed")
+	}
+
+	pem, err := a.publicKeyLookup(kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch public key: %w", err)
+	}
+
+	key, method, err := parsePublicKey(pem)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public pem: %w", err)
+	}
+
+	if token.Method.Alg() != method.Alg() {
+		return nil, fmt.Errorf("token signed with %s, key[%s] is for %s", token.Method.Alg(), kid, method.Alg())
+	}
+
+	return key, nil
+}
+
 // publicKeyLookup performs a lookup for the public pem for the specified kid.
 func (a *Auth) publicKeyLookup(kid string) (string, error) {
 	pem, err := func() (string, error) {
@@ -216,9 +392,91 @@ func (a *Auth) publicKeyLookup(kid string) (string, error) {
 	return pem, nil
 }
 
-// isUserEnabled hits the database and checks the user is not disabled. If the
-// no database connection was provided, this check is skipped.
+// InvalidateKeys drops the cached public keys for the specified kids, so a
+// key that was replaced or retired in the key lookup stops being used.
+func (a *Auth) InvalidateKeys(kids ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, kid := range kids {
+		delete(a.cache, kid)
+	}
+}
+
+// managersOf returns the ids of the managers the user reports to. There are
+// none when no lookup was provided.
+func (a *Auth) managersOf(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	if a.reportingChain == nil || userID == uuid.Nil {
+		return []string{}, nil
+	}
+
+	chain, err := a.reportingChain.ReportingChain(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	managers := make([]string, len(chain))
+	for i, id := range chain {
+		managers[i] = id.String()
+	}
+	return managers, nil
+}
+
+// rolePermissions returns the permissions granted by each role. No role
+// grants anything when no lookup was provided.
+func (a *Auth) rolePermissions(ctx context.Context) (map[string][]string, error) {
+	if a.permissions == nil {
+		return map[string][]string{}, nil
+	}
+	return a.permissions.Permissions(ctx)
+}
+
+// isRevoked checks the token id against the revoked tokens. Only tokens
+// that carry an id can be revoked, so they are required once a lookup was
+// provided.
+func (a *Auth) isRevoked(ctx context.Context, claims Claims) error {
+	if a.revocations == nil {
+		return nil
+	}
+
+	if claims.ID == "" {
+		return errors.New("token id missing from claims")
+	}
+
+	revoked, err := a.revocations.IsRevoked(ctx, claims.ID, claims.SessionID)
+	if err != nil {
+		return fmt.Errorf("revocation lookup: %w", err)
+	}
+	if revoked {
+		return errors.New("token revoked")
+	}
+
+	return nil
+}
+
+// isUserEnabled hits the database and checks the user is not disabled. If
+// no user lookup was provided, this check is skipped.
 func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
+	if a.users == nil {
+		return nil
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return fmt.Errorf("parsing subject: %w", err)
+	}
+
+	// The caller's tenant isn't in the context yet, the lookup is scoped to
+	// the one the token was issued for.
+	ctx = tenant.Set(ctx, claims.TenantID)
+
+	enabled, err := a.users.IsEnabled(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user lookup: %w", err)
+	}
+	if !enabled {
+		return errors.New("user disabled")
+	}
 
 	return nil
 }
@@ -0,0 +1,521 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sales-api/business/core/decision"
+	"sales-api/business/core/user"
+	"sales-api/foundation/keystore"
+	"sales-api/foundation/logger"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+func TestAuthenticate(t *testing.T) {
+	a := newTestAuth(t, Config{
+		Issuer:          "service project",
+		AcceptedIssuers: []string{"partner"},
+		Audience:        "sales-api",
+		Leeway:          time.Minute,
+	})
+
+	now := time.Now()
+
+	tt := []struct {
+		name  string
+		claim func(c *Claims)
+		valid bool
+	}{
+		{"valid", func(c *Claims) {}, true},
+		{"accepted issuer", func(c *Claims) { c.Issuer = "partner" }, true},
+		{"unknown issuer", func(c *Claims) { c.Issuer = "someone else" }, false},
+		{"wrong audience", func(c *Claims) { c.Audience = jwt.ClaimStrings{"billing-api"} }, false},
+		{"expired", func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-2 * time.Minute)) }, false},
+		{"expired within leeway", func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-30 * time.Second)) }, true},
+		{"no expiry", func(c *Claims) { c.ExpiresAt = nil }, false},
+		{"not before", func(c *Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(2 * time.Minute)) }, false},
+		{"not before within leeway", func(c *Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(30 * time.Second)) }, true},
+		{"issued in the future", func(c *Claims) { c.IssuedAt = jwt.NewNumericDate(now.Add(2 * time.Minute)) }, false},
+	}
+
+	for _, tst := range tt {
+		t.Run(tst.name, func(t *testing.T) {
+			claims := testClaims()
+			tst.claim(&claims)
+
+			token, err := a.GenerateToken(claims)
+			if err != nil {
+				t.Fatalf("Should be able to generate a token : %s", err)
+			}
+
+			_, err = a.Authenticate(context.Background(), "Bearer "+token)
+			if tst.valid && err != nil {
+				t.Errorf("Should accept the token : %s", err)
+			}
+			if !tst.valid && err == nil {
+				t.Error("Should refuse the token")
+			}
+		})
+	}
+}
+
+func TestSigningMethods(t *testing.T) {
+	tt := []struct {
+		kid string
+		alg string
+	}{
+		{kidRS256, "RS256"},
+		{kidES256, "ES256"},
+		{kidEdDSA, "EdDSA"},
+	}
+
+	for _, tst := range tt {
+		t.Run(tst.alg, func(t *testing.T) {
+			a := newTestAuth(t, Config{ActiveKID: tst.kid})
+
+			token, err := a.GenerateToken(testClaims())
+			if err != nil {
+				t.Fatalf("Should be able to generate a token : %s", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("Should be able to parse the token : %s", err)
+			}
+			if parsed.Method.Alg() != tst.alg || parsed.Header["kid"] != tst.kid {
+				t.Fatalf("Should sign with %s under kid %s, got %s under %v", tst.alg, tst.kid, parsed.Method.Alg(), parsed.Header["kid"])
+			}
+
+			if _, err := a.Authenticate(context.Background(), "Bearer "+token); err != nil {
+				t.Fatalf("Should accept the token : %s", err)
+			}
+		})
+	}
+}
+
+func TestVerificationKey(t *testing.T) {
+	a := newTestAuth(t, Config{})
+
+	tt := []struct {
+		name   string
+		method jwt.SigningMethod
+		header map[string]any
+		valid  bool
+	}{
+		{"rsa key", jwt.SigningMethodRS256, map[string]any{"kid": kidRS256}, true},
+		{"ecdsa key", jwt.SigningMethodES256, map[string]any{"kid": kidES256}, true},
+		{"ed25519 key", jwt.SigningMethodEdDSA, map[string]any{"kid": kidEdDSA}, true},
+		{"rsa alg for ecdsa key", jwt.SigningMethodRS256, map[string]any{"kid": kidES256}, false},
+		{"ecdsa alg for ed25519 key", jwt.SigningMethodES256, map[string]any{"kid": kidEdDSA}, false},
+		{"ed25519 alg for rsa key", jwt.SigningMethodEdDSA, map[string]any{"kid": kidRS256}, false},
+		{"hmac alg for rsa key", jwt.SigningMethodHS256, map[string]any{"kid": kidRS256}, false},
+		{"unknown kid", jwt.SigningMethodRS256, map[string]any{"kid": "unknown"}, false},
+		{"kid missing", jwt.SigningMethodRS256, map[string]any{}, false},
+		{"kid malformed", jwt.SigningMethodRS256, map[string]any{"kid": 1}, false},
+	}
+
+	for _, tst := range tt {
+		t.Run(tst.name, func(t *testing.T) {
+			_, err := a.verificationKey(&jwt.Token{Method: tst.method, Header: tst.header})
+			if tst.valid && err != nil {
+				t.Errorf("Should return the key : %s", err)
+			}
+			if !tst.valid && err == nil {
+				t.Error("Should refuse the token")
+			}
+		})
+	}
+
+	// A token signed by one key but naming another fails as a whole.
+	pemRSA, err := a.keyLookup.PrivateKey(kidRS256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, _, err := parsePrivateKey(pemRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	token.Header["kid"] = kidES256
+	str, err := token.SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Authenticate(context.Background(), "Bearer "+str); err == nil {
+		t.Error("Should refuse a token whose kid names a key of another type")
+	}
+}
+
+func TestRetiredKey(t *testing.T) {
+	a := newTestAuth(t, Config{})
+	ctx := context.Background()
+
+	token, err := a.GenerateToken(testClaims())
+	if err != nil {
+		t.Fatalf("Should be able to generate a token : %s", err)
+	}
+	if _, err := a.Authenticate(ctx, "Bearer "+token); err != nil {
+		t.Fatalf("Should accept the token : %s", err)
+	}
+
+	ks := a.keyLookup.(*keystore.KeyStore)
+	if err := ks.Retire(a.activeKID); err != nil {
+		t.Fatalf("Should be able to retire the key : %s", err)
+	}
+	a.InvalidateKeys(a.activeKID)
+
+	if _, err := a.Authenticate(ctx, "Bearer "+token); err == nil {
+		t.Error("Should refuse a token signed with a retired key")
+	}
+	if _, err := a.GenerateToken(testClaims()); err == nil {
+		t.Error("Should not sign with a retired key")
+	}
+}
+
+func TestPolicyBundle(t *testing.T) {
+	dir := t.TempDir()
+	writeBundle(t, dir, "v1", testPolicy, `{"lockdown": true}`)
+
+	a := newTestAuth(t, Config{PolicyPath: dir})
+	claims := testClaims()
+	ctx := context.Background()
+
+	if v := a.PolicyVersion(); v != "v1" {
+		t.Fatalf("Should use the bundle revision, got %q", v)
+	}
+	if err := a.Authorize(ctx, claims, uuid.Nil, RuleAdminOnly); err == nil {
+		t.Fatal("Should refuse admins while the data document locks them out")
+	}
+
+	writeBundle(t, dir, "v2", testPolicy, `{"lockdown": false}`)
+	if v, err := a.ReloadPolicies(ctx); err != nil || v != "v2" {
+		t.Fatalf("Should reload the bundle, got %q : %v", v, err)
+	}
+	if err := a.Authorize(ctx, claims, uuid.Nil, RuleAdminOnly); err != nil {
+		t.Fatalf("Should authorize admins after the reload : %s", err)
+	}
+
+	writeBundle(t, dir, "v3", "package ardan.rego\n\nruleAny {", `{}`)
+	if v, err := a.ReloadPolicies(ctx); err == nil || v != "v2" {
+		t.Fatalf("Should keep the policies in use when the bundle doesn't compile, got %q : %v", v, err)
+	}
+	if err := a.Authorize(ctx, claims, uuid.Nil, RuleAdminOnly); err != nil {
+		t.Fatalf("Should still authorize admins : %s", err)
+	}
+
+	missing := t.TempDir()
+	writeBundle(t, missing, "v1", "package ardan.rego\n\nruleAny := true\n", `{}`)
+	if _, err := bundlePolicies(ctx, missing); err == nil {
+		t.Fatal("Should refuse a bundle that doesn't define every rule")
+	}
+
+	b := newTestAuth(t, Config{PolicyPath: filepath.Join(dir, "missing")})
+	if v := b.PolicyVersion(); v != PolicyEmbedded {
+		t.Fatalf("Should fall back to the embedded policies, got %q", v)
+	}
+	if err := b.Authorize(ctx, claims, uuid.Nil, RuleAdminOnly); err != nil {
+		t.Fatalf("Should authorize admins with the embedded policies : %s", err)
+	}
+}
+
+func TestDecisions(t *testing.T) {
+	sink := &testSink{}
+	a := newTestAuth(t, Config{Decisions: []DecisionSink{sink}})
+
+	claims := testClaims()
+	target := uuid.New()
+	ctx := SetRoute(context.Background(), "GET /v1/users/{user_id}")
+
+	if err := a.Authorize(ctx, claims, target, RuleAdminOrSubject); err != nil {
+		t.Fatalf("Should authorize the admin : %s", err)
+	}
+
+	claims.Roles = []user.Role{user.RoleUser}
+	if err := a.Authorize(ctx, claims, target, RuleAdminOnly); err == nil {
+		t.Fatal("Should refuse the user")
+	}
+
+	if err := a.Authorize(ctx, claims, target, RuleAdminOrSubject); err == nil {
+		t.Fatal("Should refuse the user acting on someone else")
+	}
+
+	if len(sink.decisions) != 3 {
+		t.Fatalf("Should record every decision, got %d", len(sink.decisions))
+	}
+
+	allowed, denied, mismatch := sink.decisions[0], sink.decisions[1], sink.decisions[2]
+	if !allowed.Allowed || allowed.Reason != "" || allowed.Rule != RuleAdminOrSubject {
+		t.Errorf("Should record the allowed decision, got %+v", allowed)
+	}
+	if denied.Allowed || denied.Rule != RuleAdminOnly {
+		t.Errorf("Should record the denied decision, got %+v", denied)
+	}
+	if !strings.Contains(denied.Reason, "roles [USER] granting [reports self]: missing permission admin") {
+		t.Errorf("Should record what was missing, got %q", denied.Reason)
+	}
+	if !strings.Contains(mismatch.Reason, "is not user "+target.String()) {
+		t.Errorf("Should record the subject mismatch, got %q", mismatch.Reason)
+	}
+
+	for _, d := range sink.decisions {
+		if d.Subject != claims.Subject || d.TenantID != claims.TenantID || d.UserID != target {
+			t.Errorf("Should record who asked for whom, got %+v", d)
+		}
+		if d.Route != "GET /v1/users/{user_id}" {
+			t.Errorf("Should record the route, got %q", d.Route)
+		}
+		if d.PolicyVersion != PolicyEmbedded {
+			t.Errorf("Should record the policy version, got %q", d.PolicyVersion)
+		}
+		if d.Latency <= 0 {
+			t.Errorf("Should record the latency, got %v", d.Latency)
+		}
+	}
+}
+
+// =============================================================================
+
+// The benchmarks compare evaluating the prepared queries with preparing the
+// query on every call, which is what every request used to pay for.
+//
+//	go test -run none -bench . -benchmem ./business/web/v1/auth
+
+func BenchmarkAuthenticate(b *testing.B) {
+	a := newTestAuth(b, Config{Issuer: "service project"})
+
+	token, err := a.GenerateToken(testClaims())
+	if err != nil {
+		b.Fatal(err)
+	}
+	bearer := "Bearer " + token
+
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.Authenticate(ctx, bearer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAuthorize(b *testing.B) {
+	a := newTestAuth(b, Config{Issuer: "service project"})
+	claims := testClaims()
+
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := a.Authorize(ctx, claims, uuid.Nil, RuleAdminOnly); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAuthorizeUnprepared(b *testing.B) {
+	a := newTestAuth(b, Config{Issuer: "service project"})
+	claims := testClaims()
+
+	ctx := context.Background()
+
+	permissions, err := a.rolePermissions(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	input := map[string]any{
+		"Roles":       claims.Roles,
+		"Permissions": permissions,
+		"Subject":     claims.Subject,
+		"UserID":      uuid.Nil,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q, err := rego.New(
+			rego.Query(fmt.Sprintf("x = data.%s.%s", opaPackage, RuleAdminOnly)),
+			rego.Module("policy.rego", opaAuthorization),
+		).PrepareForEval(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if _, err := q.Eval(ctx, rego.EvalInput(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// =============================================================================
+
+type testPermissions struct{}
+
+func (testPermissions) Permissions(ctx context.Context) (map[string][]string, error) {
+	return map[string][]string{
+		"ADMIN": {"admin"},
+		"USER":  {"self", "reports"},
+	}, nil
+}
+
+type testSink struct {
+	decisions []decision.Decision
+}
+
+func (s *testSink) Record(ctx context.Context, d decision.Decision) error {
+	s.decisions = append(s.decisions, d)
+	return nil
+}
+
+// Test keys are stored under kids named for the algorithm they sign with.
+// Tokens are signed with the RSA key unless the config names another.
+const (
+	kidRS256 = "rs256"
+	kidES256 = "es256"
+	kidEdDSA = "eddsa"
+)
+
+// newTestAuth constructs an Auth with freshly generated keys, the rest of
+// the configuration comes from cfg.
+func newTestAuth(tb testing.TB, cfg Config) *Auth {
+	tb.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	ks := keystore.NewMap(map[string]keystore.PrivateKey{
+		kidRS256: testPrivateKey(tb, rsaKey),
+		kidES256: testPrivateKey(tb, ecKey),
+		kidEdDSA: testPrivateKey(tb, edKey),
+	})
+
+	cfg.Log = logger.New(io.Discard, logger.LevelError, "TEST", func(context.Context) string { return "" })
+	cfg.KeyLookup = ks
+	if cfg.ActiveKID == "" {
+		cfg.ActiveKID = kidRS256
+	}
+	cfg.Permissions = testPermissions{}
+
+	a, err := New(cfg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return a
+}
+
+// testPrivateKey PEM encodes the key the way the keys folder holds it.
+func testPrivateKey(tb testing.TB, pk crypto.Signer) keystore.PrivateKey {
+	tb.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	block := pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}
+	if err := pem.Encode(&buf, &block); err != nil {
+		tb.Fatal(err)
+	}
+
+	return keystore.PrivateKey{PK: pk, PEM: buf.Bytes()}
+}
+
+func testClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		TenantID: uuid.New(),
+		Roles:    []user.Role{user.RoleAdmin},
+	}
+}
+
+// testPolicy defines every rule, admins are locked out while the lockdown
+// data document is true.
+const testPolicy = `package ardan.rego
+
+default ruleAny = false
+default ruleAdminOnly = false
+default ruleUserOnly = false
+default ruleAdminOrSubject = false
+default ruleAdminOrSubjectOrManager = false
+
+admin {
+	perm := input.Permissions[input.Roles[_]][_]
+	perm == "admin"
+}
+
+ruleAny {
+	count(input.Roles) > 0
+}
+
+ruleAdminOnly {
+	admin
+	not data.lockdown
+}
+
+ruleUserOnly {
+	not admin
+}
+
+ruleAdminOrSubject {
+	admin
+}
+
+ruleAdminOrSubjectOrManager {
+	admin
+}
+`
+
+// writeBundle replaces the policy, data document and manifest of the
+// bundle in dir.
+func writeBundle(t *testing.T, dir string, revision string, policy string, data string) {
+	t.Helper()
+
+	files := map[string]string{
+		".manifest":   fmt.Sprintf(`{"revision": %q}`, revision),
+		"policy.rego": policy,
+		"data.json":   data,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
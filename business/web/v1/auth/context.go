@@ -15,6 +15,9 @@ const claimKey ctxKey = 1
 // key is used to store/retrieve a user value from a context.Context.
 const userKey ctxKey = 2
 
+// key is used to store/retrieve a route value from a context.Context.
+const routeKey ctxKey = 3
+
 // =============================================================================
 
 // SetClaims stores the claims in the context.
@@ -44,3 +47,17 @@ func GetUserID(ctx context.Context) uuid.UUID {
 	}
 	return v
 }
+
+// SetRoute stores the route of the request being authorized in the context.
+func SetRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+// GetRoute returns the route from the context.
+func GetRoute(ctx context.Context) string {
+	v, ok := ctx.Value(routeKey).(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"sales-api/business/core/decision"
+	"sales-api/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DecisionSink declares a method set of behavior for recording the
+// authorization decisions, so they can be audited.
+type DecisionSink interface {
+	Record(ctx context.Context, d decision.Decision) error
+}
+
+// LogSink records authorization decisions in the structured log.
+type LogSink struct {
+	log *logger.Logger
+}
+
+// NewLogSink constructs a sink that writes decisions to the log.
+func NewLogSink(log *logger.Logger) *LogSink {
+	return &LogSink{
+		log: log,
+	}
+}
+
+// Record writes the decision to the log.
+func (s *LogSink) Record(ctx context.Context, d decision.Decision) error {
+	s.log.Info(ctx, "authorize", "subject", d.Subject, "rule", d.Rule, "user_id", d.UserID, "route", d.Route,
+		"allowed", d.Allowed, "reason", d.Reason, "policy_version", d.PolicyVersion, "latency", d.Latency)
+	return nil
+}
+
+// =============================================================================
+
+// recordDecision hands the decision to every sink. A sink that fails is
+// logged and doesn't change the outcome of the request.
+func (a *Auth) recordDecision(ctx context.Context, claims Claims, userID uuid.UUID, rule string, version string, start time.Time, err error) {
+	if len(a.decisions) == 0 {
+		return
+	}
+
+	d := decision.Decision{
+		TenantID:      claims.TenantID,
+		Subject:       claims.Subject,
+		Rule:          rule,
+		UserID:        userID,
+		Route:         GetRoute(ctx),
+		Allowed:       err == nil,
+		PolicyVersion: version,
+		Latency:       time.Since(start),
+		CreatedAt:     start,
+	}
+	if err != nil {
+		d.Reason = err.Error()
+	}
+
+	for _, sink := range a.decisions {
+		if err := sink.Record(ctx, d); err != nil {
+			a.log.Error(ctx, "auth: recording decision", "rule", rule, "subject", claims.Subject, "ERROR", err)
+		}
+	}
+}
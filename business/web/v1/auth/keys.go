@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// validMethods are the signing methods tokens can be signed with. Which one
+// is used depends on the type of the key.
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// parsePrivateKey parses a PEM encoded private key and returns the signing
+// method that goes with it.
+func parsePrivateKey(privatePEM string) (crypto.PrivateKey, jwt.SigningMethod, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, nil, errors.New("no pem block")
+	}
+
+	// The block type isn't always accurate, every encoding is tried.
+	var key any
+	var err error
+	if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+				return nil, nil, errors.New("key must be a PKCS8, PKCS1 or EC private key")
+			}
+		}
+	}
+
+	var public crypto.PublicKey
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		public = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		public = &key.PublicKey
+	case ed25519.PrivateKey:
+		public = key.Public()
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type %T", key)
+	}
+
+	method, err := signingMethod(public)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, method, nil
+}
+
+// parsePublicKey parses a PEM encoded public key and returns the signing
+// method that goes with it.
+func parsePublicKey(publicPEM string) (crypto.PublicKey, jwt.SigningMethod, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return nil, nil, errors.New("no pem block")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	method, err := signingMethod(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, method, nil
+}
+
+// signingMethod picks the signing method for the type of key.
+func signingMethod(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported curve %s", key.Curve.Params().Name)
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %T", public)
+}
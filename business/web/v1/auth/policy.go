@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// PolicyEmbedded is the version reported for the policies built into the
+// service.
+const PolicyEmbedded = "embedded"
+
+// policySet is a compiled set of policies with a query prepared for every
+// rule and the version they were loaded from.
+type policySet struct {
+	version string
+	queries map[string]rego.PreparedEvalQuery
+}
+
+// PolicyVersion returns the version of the policies rules are evaluated
+// against. That is the revision of the bundle, or PolicyEmbedded.
+func (a *Auth) PolicyVersion() string {
+	return a.currentPolicy().version
+}
+
+// ReloadPolicies loads the policy bundle again and starts evaluating rules
+// against it. When the bundle can't be loaded or doesn't compile, the
+// policies in use are kept and the error is returned. It returns the
+// version of the policies in use.
+func (a *Auth) ReloadPolicies(ctx context.Context) (string, error) {
+	if a.policyPath == "" {
+		return a.PolicyVersion(), nil
+	}
+
+	ps, err := bundlePolicies(ctx, a.policyPath)
+	if err != nil {
+		return a.PolicyVersion(), err
+	}
+
+	a.policyMu.Lock()
+	defer a.policyMu.Unlock()
+	a.policy = ps
+
+	return ps.version, nil
+}
+
+// currentPolicy returns the policies rules are evaluated against. A reload
+// doesn't change the set that was returned.
+func (a *Auth) currentPolicy() policySet {
+	a.policyMu.RLock()
+	defer a.policyMu.RUnlock()
+
+	return a.policy
+}
+
+// =============================================================================
+
+// embeddedPolicies prepares the queries against the policies built into the
+// service.
+func embeddedPolicies(ctx context.Context) (policySet, error) {
+	queries, err := prepareQueries(ctx, func(rule string) func(*rego.Rego) {
+		return rego.Module("policy.rego", policies[rule])
+	})
+	if err != nil {
+		return policySet{}, err
+	}
+
+	return policySet{version: PolicyEmbedded, queries: queries}, nil
+}
+
+// bundlePolicies loads the policies and data documents from a bundle, a
+// directory or a tarball, and prepares the queries against them. The bundle
+// has to define every rule the service uses.
+func bundlePolicies(ctx context.Context, path string) (policySet, error) {
+	b, err := loader.NewFileLoader().AsBundle(path)
+	if err != nil {
+		return policySet{}, fmt.Errorf("loading bundle[%s]: %w", path, err)
+	}
+
+	if err := checkRules(b); err != nil {
+		return policySet{}, fmt.Errorf("bundle[%s]: %w", path, err)
+	}
+
+	queries, err := prepareQueries(ctx, func(string) func(*rego.Rego) {
+		return rego.ParsedBundle(path, b)
+	})
+	if err != nil {
+		return policySet{}, fmt.Errorf("bundle[%s]: %w", path, err)
+	}
+
+	version := b.Manifest.Revision
+	if version == "" {
+		version = path
+	}
+
+	return policySet{version: version, queries: queries}, nil
+}
+
+// prepareQueries compiles the query for every rule once, so a request only
+// pays for evaluating it. The source provides the policies for a rule.
+func prepareQueries(ctx context.Context, source func(rule string) func(*rego.Rego)) (map[string]rego.PreparedEvalQuery, error) {
+	queries := make(map[string]rego.PreparedEvalQuery, len(policies))
+	for rule := range policies {
+		query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
+
+		q, err := rego.New(
+			rego.Query(query),
+			source(rule),
+		).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("rule[%s]: %w", rule, err)
+		}
+		queries[rule] = q
+	}
+	return queries, nil
+}
+
+// checkRules makes sure the bundle defines every rule in our package. A
+// query for a rule that isn't defined compiles fine and denies everything.
+func checkRules(b *bundle.Bundle) error {
+	pkg := ast.MustParseRef("data." + opaPackage)
+
+	defined := make(map[string]bool)
+	for _, mf := range b.Modules {
+		if mf.Parsed == nil || !mf.Parsed.Package.Path.Equal(pkg) {
+			continue
+		}
+		for _, r := range mf.Parsed.Rules {
+			defined[r.Head.Ref().String()] = true
+		}
+	}
+
+	var missing []string
+	for rule := range policies {
+		if !defined[rule] {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("rules %v not defined in package %s", missing, opaPackage)
+	}
+
+	return nil
+}
@@ -4,36 +4,39 @@ default ruleAny = false
 default ruleAdminOnly = false
 default ruleUserOnly = false
 default ruleAdminOrSubject = false
+default ruleAdminOrSubjectOrManager = false
 
-roleUser := "USER"
-roleAdmin := "ADMIN"
-roleAll := {roleAdmin, roleUser}
+permAdmin := "admin"
+permSelf := "self"
+permReports := "reports"
+
+# The permissions granted by the roles in the claims. The role to permission
+# mapping is kept in the database and handed in with the input.
+claim_permissions := {perm | role := input.Roles[_]; perm := input.Permissions[role][_]}
 
 ruleAny {
-	claim_roles := {role | role := input.Roles[_]}
-	input_roles := roleAll & claim_roles
-	count(input_roles) > 0
+	known_roles := {role | role := input.Roles[_]; input.Permissions[role]}
+	count(known_roles) > 0
 }
 
 ruleAdminOnly {
-	claim_roles := {role | role := input.Roles[_]}
-	input_admin := {roleAdmin} & claim_roles
-	count(input_admin) > 0
+	claim_permissions[permAdmin]
 }
 
 ruleUserOnly {
-	claim_roles := {role | role := input.Roles[_]}
-	input_user := {roleUser} & claim_roles
-	count(input_user) > 0
+	claim_permissions[permSelf]
 }
 
 ruleAdminOrSubject {
-	claim_roles := {role | role := input.Roles[_]}
-	input_admin := {roleAdmin} & claim_roles
-    count(input_admin) > 0
+	claim_permissions[permAdmin]
 } else {
-    claim_roles := {role | role := input.Roles[_]}
-	input_user := {roleUser} & claim_roles
-	count(input_user) > 0
+	claim_permissions[permSelf]
 	input.UserID == input.Subject
 }
+
+ruleAdminOrSubjectOrManager {
+	ruleAdminOrSubject
+} else {
+	claim_permissions[permReports]
+	input.Managers[_] == input.Subject
+}
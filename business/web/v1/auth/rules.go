@@ -2,17 +2,106 @@ package auth
 
 import (
 	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
 // These the current set of rules we have for auth.
 const (
-	RuleAuthenticate   = "auth"
-	RuleAny            = "ruleAny"
-	RuleAdminOnly      = "ruleAdminOnly"
-	RuleUserOnly       = "ruleUserOnly"
-	RuleAdminOrSubject = "ruleAdminOrSubject"
+	RuleAny                     = "ruleAny"
+	RuleAdminOnly               = "ruleAdminOnly"
+	RuleUserOnly                = "ruleUserOnly"
+	RuleAdminOrSubject          = "ruleAdminOrSubject"
+	RuleAdminOrSubjectOrManager = "ruleAdminOrSubjectOrManager"
 )
 
+// rulesWithManagers are the rules that need the reporting chain of the
+// target user as input.
+var rulesWithManagers = map[string]bool{
+	RuleAdminOrSubjectOrManager: true,
+}
+
+// policies maps every rule to the policy it is defined in. A query is
+// prepared for each of them when Auth is constructed.
+var policies = map[string]string{
+	RuleAny:                     opaAuthorization,
+	RuleAdminOnly:               opaAuthorization,
+	RuleUserOnly:                opaAuthorization,
+	RuleAdminOrSubject:          opaAuthorization,
+	RuleAdminOrSubjectOrManager: opaAuthorization,
+}
+
+// Permissions the rules in authorization.rego ask for.
+const (
+	permAdmin   = "admin"
+	permSelf    = "self"
+	permReports = "reports"
+)
+
+// explainDenial describes a denied rule for whoever audits the decision:
+// the rule, the roles and the permissions they grant, and what was missing.
+// It follows the embedded policies, a bundle that changes what a rule asks
+// for makes it approximate.
+func explainDenial(rule string, claims Claims, permissions map[string][]string, userID uuid.UUID, managers []string) string {
+	roles := make([]string, len(claims.Roles))
+	granted := make(map[string]bool)
+	for i, role := range claims.Roles {
+		roles[i] = role.Name()
+		for _, perm := range permissions[role.Name()] {
+			granted[perm] = true
+		}
+	}
+
+	perms := make([]string, 0, len(granted))
+	for perm := range granted {
+		perms = append(perms, perm)
+	}
+	sort.Strings(perms)
+
+	missing := func(perm string) string {
+		return "missing permission " + perm
+	}
+
+	var why []string
+	switch rule {
+	case RuleAny:
+		why = append(why, "no role the service knows")
+
+	case RuleAdminOnly:
+		why = append(why, missing(permAdmin))
+
+	case RuleUserOnly:
+		why = append(why, missing(permSelf))
+
+	case RuleAdminOrSubject, RuleAdminOrSubjectOrManager:
+		why = append(why, missing(permAdmin))
+
+		switch {
+		case !granted[permSelf]:
+			why = append(why, missing(permSelf))
+		case claims.Subject != userID.String():
+			why = append(why, fmt.Sprintf("subject %s is not user %s", claims.Subject, userID))
+		}
+
+		if rule == RuleAdminOrSubjectOrManager {
+			switch {
+			case !granted[permReports]:
+				why = append(why, missing(permReports))
+			default:
+				why = append(why, fmt.Sprintf("subject %s is not among the managers %v of user %s", claims.Subject, managers, userID))
+			}
+		}
+
+	default:
+		why = append(why, "policy denied")
+	}
+
+	return fmt.Sprintf("rule %s denied roles %v granting %v: %s", rule, roles, perms, strings.Join(why, ", "))
+}
+
 // Package name of our rego code.
 const (
 	opaPackage string = "ardan.rego"
@@ -20,9 +109,6 @@ const (
 
 // Core OPA policies.
 var (
-	//go:embed rego/authentication.rego
-	opaAuthentication string
-
 	//go:embed rego/authorization.rego
 	opaAuthorization string
 )
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sales-api/business/data/actor"
+	"sales-api/business/data/tenant"
 	"sales-api/business/web/v1/auth"
 	"sales-api/business/web/v1/response"
 	"sales-api/foundation/web"
@@ -25,6 +27,10 @@ func Authenticate(a *auth.Auth) web.Middleware {
 				return auth.NewAuthError("authenticate: failed: %s", err)
 			}
 			ctx = auth.SetClaims(ctx, claims)
+			ctx = tenant.Set(ctx, claims.TenantID)
+			if userID, err := uuid.Parse(claims.Subject); err == nil {
+				ctx = actor.Set(ctx, userID)
+			}
 			return handler(ctx, w, r)
 
 		}
@@ -54,9 +60,18 @@ func Authorize(a *auth.Auth, rule string) web.Middleware {
 				ctx = auth.SetUserID(ctx, userID)
 			}
 
+			ctx = auth.SetRoute(ctx, web.Route(r))
+
 			if err := a.Authorize(ctx, claims, userID, rule); err != nil {
 				return auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, rule, err)
 			}
+
+			// The database only shows the appointments of the acting user
+			// and the user they were authorized to act on.
+			if userID != uuid.Nil {
+				ctx = actor.SetTarget(ctx, userID)
+			}
+
 			return handler(ctx, w, r)
 
 		}
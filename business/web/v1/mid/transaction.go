@@ -19,7 +19,7 @@ func ExecuteInTransaction(log *logger.Logger, bgn transaction.Beginner) web.Midd
 
 			hasCommited := false
 			log.Info(ctx, "BEGIN TRANSACTION")
-			tx, err := bgn.Begin()
+			tx, err := bgn.Begin(ctx)
 			if err != nil {
 				return fmt.Errorf("BEGIN TRANSACTION: %w", err)
 			}
@@ -47,6 +47,8 @@ func ExecuteInTransaction(log *logger.Logger, bgn transaction.Beginner) web.Midd
 			}
 
 			hasCommited = true
+			transaction.Committed(ctx)
+
 			return nil
 
 		}
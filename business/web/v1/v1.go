@@ -1,7 +1,10 @@
 package v1
 
 import (
+	"net/url"
 	"os"
+	"sales-api/business/core/role"
+	"sales-api/business/core/user"
 	"sales-api/business/web/v1/auth"
 	"sales-api/business/web/v1/mid"
 	"sales-api/foundation/logger"
@@ -10,13 +13,21 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-// APIMuxConfig contains all the mandatory systems required by handlers.
+// APIMuxConfig contains all the mandatory systems required by handlers. The
+// caches are shared with auth, so changes made through the handlers are seen
+// when authenticating. MaintenanceDB bypasses row level security and is only
+// for work that has to find the tenant first.
 type APIMuxConfig struct {
-	Build    string
-	Shutdown chan os.Signal
-	Log      *logger.Logger
-	Auth     *auth.Auth
-	DB       *sqlx.DB
+	Build         string
+	Shutdown      chan os.Signal
+	Log           *logger.Logger
+	Auth          *auth.Auth
+	Keys          auth.KeySet
+	DB            *sqlx.DB
+	MaintenanceDB *sqlx.DB
+	UserCache     *user.EnabledCache
+	RoleCache     *role.Cache
+	PublicURL     *url.URL
 }
 
 type RouteAdder interface {
@@ -4,7 +4,8 @@ package keystore
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"crypto"
+	"crypto/elliptic"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
@@ -12,43 +13,136 @@ import (
 	"io"
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// PrivateKey represents key information.
+// PrivateKey represents key information. The key is an RSA, ECDSA P-256 or
+// Ed25519 private key.
 type PrivateKey struct {
-	PK  *rsa.PrivateKey
+	PK  crypto.Signer
 	PEM []byte
 }
 
 // KeyStore represents an in memory store implementation of the
-// KeyLookup interface for use with the auth package.
+// KeyLookup interface for use with the auth package. A key is active until
+// it is retired. Retired keys are kept so they can be listed but are no
+// longer handed out for signing or verifying tokens.
 type KeyStore struct {
-	store map[string]PrivateKey
+	mu      sync.RWMutex
+	fsys    fs.FS
+	store   map[string]PrivateKey
+	retired map[string]time.Time
+	removed map[string]time.Time
 }
 
 // New constructs an empty KeyStore ready for use.
 func New() *KeyStore {
 	return &KeyStore{
-		store: make(map[string]PrivateKey),
+		store:   make(map[string]PrivateKey),
+		retired: make(map[string]time.Time),
+		removed: make(map[string]time.Time),
 	}
 }
 
 // NewMap constructs a KeyStore with an initial set of keys.
 func NewMap(store map[string]PrivateKey) *KeyStore {
-	return &KeyStore{
-		store: store,
-	}
+	ks := New()
+	ks.store = store
+	return ks
 }
 
 // NewFS constructs a KeyStore based on a set of PEM files rooted inside
 // of a directory. The name of each PEM file will be used as the key id.
+// Call Rescan to pick up changes made to the directory afterwards.
 // Example: keystore.NewFS(os.DirFS("/zarf/keys/"))
 // Example: /zarf/keys/54bb2165-71e1-41a6-af3e-7da4a0e1e2c1.pem
 func NewFS(fsys fs.FS) (*KeyStore, error) {
 	ks := New()
+	ks.fsys = fsys
+
+	keys, err := readFS(fsys)
+	if err != nil {
+		return nil, err
+	}
+	ks.store = keys
+
+	return ks, nil
+}
+
+// Rescan reads the directory the KeyStore was constructed from again. New
+// and changed PEM files are loaded. A key whose file was removed keeps
+// working for the grace period, so tokens it signed don't fail all at once,
+// and is retired after that. A retired key is never brought back, a key
+// that has to be replaced gets a new kid. Rescan returns the kids whose key
+// changed or was retired so cached copies can be dropped. Nothing changes
+// when the directory can't be read.
+func (ks *KeyStore) Rescan(grace time.Duration) ([]string, error) {
+	if ks.fsys == nil {
+		return nil, errors.New("keystore not backed by a directory")
+	}
+
+	keys, err := readFS(ks.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	now := time.Now()
+	var changed []string
+
+	for kid, key := range keys {
+		delete(ks.removed, kid)
+
+		if _, retired := ks.retired[kid]; retired {
+			continue
+		}
+
+		current, exists := ks.store[kid]
+		if exists && bytes.Equal(current.PEM, key.PEM) {
+			continue
+		}
+
+		ks.store[kid] = key
+		changed = append(changed, kid)
+	}
+
+	for kid := range ks.store {
+		if _, exists := keys[kid]; exists {
+			continue
+		}
+		if _, retired := ks.retired[kid]; retired {
+			continue
+		}
+
+		removedAt, seen := ks.removed[kid]
+		if !seen {
+			ks.removed[kid] = now
+			continue
+		}
+
+		if now.Sub(removedAt) >= grace {
+			ks.retired[kid] = now
+			delete(ks.removed, kid)
+			changed = append(changed, kid)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// readFS reads every PEM file rooted inside of the directory, keyed by the
+// name of the file.
+func readFS(fsys fs.FS) (map[string]PrivateKey, error) {
+	keys := make(map[string]PrivateKey)
 
 	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
@@ -77,7 +171,7 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 			return fmt.Errorf("reading auth private key: %w", err)
 		}
 
-		pk, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		pk, err := parsePrivateKey(pem)
 		if err != nil {
 			return fmt.Errorf("parsing auth private key: %w", err)
 		}
@@ -87,7 +181,7 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 			PEM: pem,
 		}
 
-		ks.store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = key
+		keys[strings.TrimSuffix(dirEntry.Name(), ".pem")] = key
 
 		return nil
 	}
@@ -96,14 +190,14 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 		return nil, fmt.Errorf("walking directory: %w", err)
 	}
 
-	return ks, nil
+	return keys, nil
 }
 
 // PrivateKey searches the key store for a given kid and returns the private key.
 func (ks *KeyStore) PrivateKey(kid string) (string, error) {
-	privateKey, found := ks.store[kid]
-	if !found {
-		return "", errors.New("kid lookup failed")
+	privateKey, err := ks.activeKey(kid)
+	if err != nil {
+		return "", err
 	}
 
 	return string(privateKey.PEM), nil
@@ -111,12 +205,12 @@ func (ks *KeyStore) PrivateKey(kid string) (string, error) {
 
 // PublicKey searches the key store for a given kid and returns the public key.
 func (ks *KeyStore) PublicKey(kid string) (string, error) {
-	privateKey, found := ks.store[kid]
-	if !found {
-		return "", errors.New("kid lookup failed")
+	privateKey, err := ks.activeKey(kid)
+	if err != nil {
+		return "", err
 	}
 
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PK.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.PK.Public())
 	if err != nil {
 		return "", fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -133,3 +227,92 @@ func (ks *KeyStore) PublicKey(kid string) (string, error) {
 
 	return b.String(), nil
 }
+
+// Retire stops the key from being used for signing or verifying tokens.
+// Tokens signed with it are refused from then on.
+func (ks *KeyStore) Retire(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, found := ks.store[kid]; !found {
+		return errors.New("kid lookup failed")
+	}
+
+	if _, retired := ks.retired[kid]; !retired {
+		ks.retired[kid] = time.Now()
+	}
+
+	return nil
+}
+
+// ActiveKIDs returns the sorted ids of the keys that can be used.
+func (ks *KeyStore) ActiveKIDs() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kids := make([]string, 0, len(ks.store))
+	for kid := range ks.store {
+		if _, retired := ks.retired[kid]; !retired {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+
+	return kids
+}
+
+// RetiredKIDs returns the sorted ids of the keys that were retired.
+func (ks *KeyStore) RetiredKIDs() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kids := make([]string, 0, len(ks.retired))
+	for kid := range ks.retired {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	return kids
+}
+
+// activeKey returns the key for the kid as long as it wasn't retired.
+func (ks *KeyStore) activeKey(kid string) (PrivateKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	privateKey, found := ks.store[kid]
+	if !found {
+		return PrivateKey{}, errors.New("kid lookup failed")
+	}
+
+	if _, retired := ks.retired[kid]; retired {
+		return PrivateKey{}, errors.New("kid retired")
+	}
+
+	return privateKey, nil
+}
+
+// parsePrivateKey parses a PEM encoded RSA, ECDSA P-256 or Ed25519 private
+// key, the key types that have a JWT signing method.
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	if pk, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return pk, nil
+	}
+
+	if pk, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		if pk.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported curve %s", pk.Curve.Params().Name)
+		}
+		return pk, nil
+	}
+
+	if pk, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		signer, ok := pk.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("ed25519 key can't sign")
+		}
+		return signer, nil
+	}
+
+	return nil, errors.New("key must be a PEM encoded RSA, ECDSA P-256 or Ed25519 private key")
+}
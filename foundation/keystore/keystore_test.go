@@ -0,0 +1,213 @@
+package keystore_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"sales-api/foundation/keystore"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRescan(t *testing.T) {
+	k1, k2, k3 := newPEM(t), newPEM(t), newPEM(t)
+
+	type step struct {
+		files   map[string][]byte
+		grace   time.Duration
+		changed []string
+		active  []string
+		retired []string
+		err     bool
+	}
+
+	tt := []struct {
+		name   string
+		files  map[string][]byte
+		retire []string
+		steps  []step
+	}{
+		{
+			name:  "new and changed keys",
+			files: map[string][]byte{"a": k1},
+			steps: []step{
+				{files: map[string][]byte{"a": k2, "b": k3}, changed: []string{"a", "b"}, active: []string{"a", "b"}},
+				{files: map[string][]byte{"a": k2, "b": k3}, active: []string{"a", "b"}},
+			},
+		},
+		{
+			name:  "removed key kept for the grace period",
+			files: map[string][]byte{"a": k1, "b": k2},
+			steps: []step{
+				{files: map[string][]byte{"a": k1}, grace: time.Hour, active: []string{"a", "b"}},
+				{files: map[string][]byte{"a": k1}, grace: time.Hour, active: []string{"a", "b"}},
+			},
+		},
+		{
+			name:  "removed key retired after the grace period",
+			files: map[string][]byte{"a": k1, "b": k2},
+			steps: []step{
+				{files: map[string][]byte{"a": k1}, active: []string{"a", "b"}},
+				{files: map[string][]byte{"a": k1}, changed: []string{"b"}, active: []string{"a"}, retired: []string{"b"}},
+			},
+		},
+		{
+			name:  "key restored within the grace period",
+			files: map[string][]byte{"a": k1, "b": k2},
+			steps: []step{
+				{files: map[string][]byte{"a": k1}, active: []string{"a", "b"}},
+				{files: map[string][]byte{"a": k1, "b": k2}, active: []string{"a", "b"}},
+				{files: map[string][]byte{"a": k1}, active: []string{"a", "b"}},
+			},
+		},
+		{
+			name:  "removed key never resurrected",
+			files: map[string][]byte{"a": k1, "b": k2},
+			steps: []step{
+				{files: map[string][]byte{"a": k1}, active: []string{"a", "b"}},
+				{files: map[string][]byte{"a": k1}, changed: []string{"b"}, active: []string{"a"}, retired: []string{"b"}},
+				{files: map[string][]byte{"a": k1, "b": k3}, active: []string{"a"}, retired: []string{"b"}},
+			},
+		},
+		{
+			name:   "retired key never resurrected",
+			files:  map[string][]byte{"a": k1, "b": k2},
+			retire: []string{"b"},
+			steps: []step{
+				{files: map[string][]byte{"a": k1, "b": k3}, active: []string{"a"}, retired: []string{"b"}},
+			},
+		},
+		{
+			name:  "unreadable key changes nothing",
+			files: map[string][]byte{"a": k1, "b": k2},
+			steps: []step{
+				{files: map[string][]byte{"a": k3, "c": []byte("not a key")}, err: true, active: []string{"a", "b"}},
+			},
+		},
+	}
+
+	for _, tst := range tt {
+		t.Run(tst.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			setFiles(fsys, tst.files)
+
+			ks, err := keystore.NewFS(fsys)
+			if err != nil {
+				t.Fatalf("Should be able to construct the keystore : %s", err)
+			}
+			for _, kid := range tst.retire {
+				if err := ks.Retire(kid); err != nil {
+					t.Fatalf("Should be able to retire %s : %s", kid, err)
+				}
+			}
+
+			for i, s := range tst.steps {
+				setFiles(fsys, s.files)
+
+				changed, err := ks.Rescan(s.grace)
+				if s.err != (err != nil) {
+					t.Fatalf("step %d: Should return an error %t, got %v", i, s.err, err)
+				}
+				if !slices.Equal(changed, s.changed) {
+					t.Errorf("step %d: Should report changed %v, got %v", i, s.changed, changed)
+				}
+				if got := ks.ActiveKIDs(); !slices.Equal(got, s.active) {
+					t.Errorf("step %d: Should have active %v, got %v", i, s.active, got)
+				}
+				if got := ks.RetiredKIDs(); !slices.Equal(got, s.retired) {
+					t.Errorf("step %d: Should have retired %v, got %v", i, s.retired, got)
+				}
+			}
+
+			// The keys handed out are the ones last read for the kid.
+			last := tst.steps[len(tst.steps)-1]
+			for _, kid := range ks.ActiveKIDs() {
+				want, ok := last.files[kid]
+				if !ok || last.err {
+					continue
+				}
+				if got, err := ks.PrivateKey(kid); err != nil || got != string(want) {
+					t.Errorf("Should hand out the current PEM for %s : %v", kid, err)
+				}
+			}
+			for _, kid := range ks.RetiredKIDs() {
+				if _, err := ks.PrivateKey(kid); err == nil {
+					t.Errorf("Should not hand out retired key %s", kid)
+				}
+			}
+		})
+	}
+}
+
+func TestRetire(t *testing.T) {
+	fsys := fstest.MapFS{}
+	setFiles(fsys, map[string][]byte{"a": newPEM(t), "b": newPEM(t)})
+
+	ks, err := keystore.NewFS(fsys)
+	if err != nil {
+		t.Fatalf("Should be able to construct the keystore : %s", err)
+	}
+
+	if got := ks.ActiveKIDs(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("Should have every key active, got %v", got)
+	}
+	if got := ks.RetiredKIDs(); len(got) != 0 {
+		t.Fatalf("Should have no retired keys, got %v", got)
+	}
+
+	if err := ks.Retire("c"); err == nil {
+		t.Fatal("Should refuse to retire an unknown kid")
+	}
+
+	for range 2 {
+		if err := ks.Retire("b"); err != nil {
+			t.Fatalf("Should be able to retire b : %s", err)
+		}
+		if got := ks.ActiveKIDs(); !slices.Equal(got, []string{"a"}) {
+			t.Fatalf("Should have only a active, got %v", got)
+		}
+		if got := ks.RetiredKIDs(); !slices.Equal(got, []string{"b"}) {
+			t.Fatalf("Should have b retired, got %v", got)
+		}
+	}
+
+	if _, err := ks.PrivateKey("b"); err == nil {
+		t.Error("Should not hand out the private key of a retired kid")
+	}
+	if _, err := ks.PublicKey("b"); err == nil {
+		t.Error("Should not hand out the public key of a retired kid")
+	}
+	if _, err := ks.PublicKey("a"); err != nil {
+		t.Errorf("Should still hand out the public key of a : %s", err)
+	}
+}
+
+// =============================================================================
+
+// setFiles replaces the PEM files in fsys with one file per kid.
+func setFiles(fsys fstest.MapFS, files map[string][]byte) {
+	clear(fsys)
+	for kid, data := range files {
+		fsys[kid+".pem"] = &fstest.MapFile{Data: data}
+	}
+}
+
+// newPEM generates a PEM encoded Ed25519 private key.
+func newPEM(t *testing.T) []byte {
+	t.Helper()
+
+	_, pk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate a key : %s", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		t.Fatalf("Should be able to marshal the key : %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
@@ -18,6 +18,21 @@ func Param(r *http.Request, key string) string {
 	return m[key]
 }
 
+// Route returns the method and path template of the route the request
+// matched, such as "GET /users/{user_id}".
+func Route(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return r.Method + " " + r.URL.Path
+	}
+
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return r.Method + " " + r.URL.Path
+	}
+	return r.Method + " " + tpl
+}
+
 // Decode reads the body of an HTTP request looking for a JSON document. The
 // body is decoded into the provided value.
 // If the provided value is a struct then it is checked for validation tags.
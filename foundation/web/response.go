@@ -30,3 +30,19 @@ func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode in
 
 	return nil
 }
+
+// RespondRaw sends the data to the client as is with the specified content
+// type. It is used for documents that are not JSON.
+func RespondRaw(ctx context.Context, w http.ResponseWriter, data []byte, contentType string, statusCode int) error {
+
+	SetStatusCode(ctx, statusCode)
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -43,7 +43,7 @@ func (a *App) SignalShutdown() {
 
 func (a *App) HandleNoMiddleWareFunc(path string, h Handler) *mux.Route {
 
-	return a.handleFunc(h, path)
+	return a.handleFunc(h, a.pathPrefix, path)
 }
 
 // HandleNoMiddleware sets a handler function for a given HTTP method and path pair
@@ -53,12 +53,23 @@ func (a *App) HandleFunc(path string, h Handler, mw ...Middleware) *mux.Route {
 	h = wrapMiddleWare(mw, h)
 	h = wrapMiddleWare(a.mw, h)
 
-	return a.handleFunc(h, path)
+	return a.handleFunc(h, a.pathPrefix, path)
+}
+
+// HandleRootFunc sets a handler function for a path that isn't under the
+// path prefix, for paths fixed by a standard such as /.well-known. It
+// includes the application middleware.
+func (a *App) HandleRootFunc(path string, h Handler, mw ...Middleware) *mux.Route {
+
+	h = wrapMiddleWare(mw, h)
+	h = wrapMiddleWare(a.mw, h)
+
+	return a.handleFunc(h, "", path)
 }
 
 // ===========================================================================
 
-func (a *App) handleFunc(h Handler, path string) *mux.Route {
+func (a *App) handleFunc(h Handler, pathPrefix string, path string) *mux.Route {
 	f := func(w http.ResponseWriter, r *http.Request) {
 
 		v := Values{
@@ -76,7 +87,11 @@ func (a *App) handleFunc(h Handler, path string) *mux.Route {
 		}
 
 	}
-	routes := a.Router.PathPrefix(a.pathPrefix).Subrouter()
+	if pathPrefix == "" {
+		return a.Router.HandleFunc(path, f)
+	}
+
+	routes := a.Router.PathPrefix(pathPrefix).Subrouter()
 
 	return routes.HandleFunc(path, f)
 }